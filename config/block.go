@@ -1,16 +1,157 @@
 package config
 
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 type Block struct {
 	In     string `json:"in"`
 	Out    string `json:"out"`
 	Format string `json:"format"`
 
+	// Layout is the template Markdown pages are rendered into. When it's set, In is a
+	// Markdown file (or a glob matching several) whose front matter is available to the
+	// layout as .Page and whose body is available as .Content.
+	Layout string `json:"layout"`
+
 	Options Options `json:"options"`
 }
 
 type Options struct {
-	Minify bool                   `json:"minify"`
+	Minify bool `json:"minify"`
+
+	// MinifyOptions configures the minifiers used when Minify is set.
+	MinifyOptions MinifyOptions `json:"minifyOptions"`
+
+	// Indent reformats xml output with each element on its own line, indented by a
+	// number of spaces or "tab". It has no effect if Minify is set. It's also the
+	// indentation of json output (four spaces by default), and of canonical yaml and
+	// toml output.
+	Indent Indent `json:"indent"`
+
+	// Schema is the path to a JSON Schema, relative to the config file, which json
+	// output must match.
+	Schema string `json:"schema"`
+
+	// SortKeys sorts the keys of every object in json output.
+	SortKeys bool `json:"sortKeys"`
+
+	// TrailingNewline ends json and plain text output with exactly one newline.
+	TrailingNewline bool `json:"trailingNewline"`
+
+	// TrimTrailingWhitespace, CollapseBlankLines, LineEndings ("lf" or "crlf") and
+	// Header clean up plain text output. Header adds a comment saying the file is
+	// generated, in the syntax for the out file's extension.
+	TrimTrailingWhitespace bool   `json:"trimTrailingWhitespace"`
+	CollapseBlankLines     bool   `json:"collapseBlankLines"`
+	LineEndings            string `json:"lineEndings"`
+	Header                 bool   `json:"header"`
+
+	// Canonical re-serializes yaml, toml and csv output, with sorted keys and
+	// consistent indentation and quoting.
+	Canonical bool `json:"canonical"`
+
 	Env    map[string]string      `json:"env"`
 	Delims [2]string              `json:"delims"`
 	Params map[string]interface{} `json:"params"`
+
+	// ErrorPage replaces the output with a page describing the error when an html
+	// block fails to render in watch mode.
+	ErrorPage bool `json:"errorPage"`
+
+	// Sandbox restricts the files a template can read to the directory containing the
+	// config file, plus the directories in SandboxAllow.
+	Sandbox      bool     `json:"sandbox"`
+	SandboxAllow []string `json:"sandboxAllow"`
+
+	// Locales renders the block once for each locale. The block's out path is a
+	// template, like "{{ .Locale }}/index.html".
+	Locales []string `json:"locales"`
+
+	// Markdown configures the markdown functions.
+	Markdown Markdown `json:"markdown"`
+
+	// DisableFuncs removes the named functions from the block's templates.
+	DisableFuncs []string `json:"disableFuncs"`
+}
+
+// Markdown configures how Markdown is converted to HTML. By default, documents are
+// converted as CommonMark with typographic punctuation, and raw HTML is passed through.
+type Markdown struct {
+	// GFM enables tables, strikethrough, task lists and autolinks.
+	GFM bool `json:"gfm"`
+
+	Tables          bool `json:"tables"`
+	Strikethrough   bool `json:"strikethrough"`
+	TaskLists       bool `json:"taskLists"`
+	Autolinks       bool `json:"autolinks"`
+	Footnotes       bool `json:"footnotes"`
+	DefinitionLists bool `json:"definitionLists"`
+
+	// HeadingIDs gives every heading an id derived from its text.
+	HeadingIDs bool `json:"headingIDs"`
+
+	// EscapeHTML escapes raw HTML instead of passing it through.
+	EscapeHTML bool `json:"escapeHTML"`
+
+	// Highlight highlights fenced code blocks using the chroma style named by
+	// HighlightStyle (default: "github"), with CSS classes instead of inline styles if
+	// HighlightClasses is set. LineNumbers numbers the lines of every block.
+	Highlight        bool   `json:"highlight"`
+	HighlightStyle   string `json:"highlightStyle"`
+	HighlightClasses bool   `json:"highlightClasses"`
+	LineNumbers      bool   `json:"lineNumbers"`
+}
+
+// MinifyOptions configures the minifiers. By default, output is minified as much as
+// possible.
+type MinifyOptions struct {
+	KeepComments            bool `json:"keepComments"`
+	KeepConditionalComments bool `json:"keepConditionalComments"`
+	KeepDefaultAttrVals     bool `json:"keepDefaultAttrVals"`
+	KeepEndTags             bool `json:"keepEndTags"`
+	KeepQuotes              bool `json:"keepQuotes"`
+	KeepWhitespace          bool `json:"keepWhitespace"`
+	KeepVarNames            bool `json:"keepVarNames"`
+
+	// Precision is the number of significant digits numbers are rounded to. Zero keeps
+	// every digit.
+	Precision int `json:"precision"`
+}
+
+// Indent is the string output is indented with. In a config file, it's either a number
+// of spaces or "tab".
+type Indent string
+
+func (i *Indent) UnmarshalJSON(by []byte) error {
+	var n int
+	if err := json.Unmarshal(by, &n); err == nil {
+		if n < 0 {
+			return errors.Errorf("indent: expected a positive number of spaces, got %d", n)
+		}
+
+		*i = Indent(strings.Repeat(" ", n))
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(by, &s); err != nil {
+		return errors.New(`indent: expected a number of spaces or "tab"`)
+	}
+
+	switch s {
+	case "tab", "tabs", "\t":
+		*i = "\t"
+	default:
+		if strings.Trim(s, " \t") != "" {
+			return errors.Errorf(`indent: expected a number of spaces or "tab", got %q`, s)
+		}
+
+		*i = Indent(s)
+	}
+
+	return nil
 }
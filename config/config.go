@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Config is the contents of a tmpl config file. For compatibility with older config
+// files, the file may also be a bare array of blocks.
+type Config struct {
+	Blocks    []Block             `json:"blocks"`
+	Functions map[string]Function `json:"functions"`
+	I18n      I18n                `json:"i18n"`
+	Assets    Assets              `json:"assets"`
+	Bundles   map[string]Bundle   `json:"bundles"`
+
+	// BaseURL is the URL the public directory is served from, like
+	// "https://example.com". It's used for the sitemap and feeds.
+	BaseURL string   `json:"baseURL"`
+	Sitemap *Sitemap `json:"sitemap"`
+	Feeds   []Feed   `json:"feeds"`
+}
+
+// Sitemap configures the sitemap written for the html blocks' output after a build.
+type Sitemap struct {
+	// Out is the path the sitemap is written to, relative to the public directory. It
+	// defaults to "sitemap.xml".
+	Out string `json:"out"`
+
+	// ChangeFreq and Priority are the defaults for every page. Pages can override them
+	// with "changefreq" and "priority" in their front matter. Priority is nil if it isn't
+	// set, so that a priority of 0 is still listed.
+	ChangeFreq string   `json:"changefreq"`
+	Priority   *float64 `json:"priority"`
+
+	// Exclude lists glob patterns, like "/drafts/**", for URL paths to leave out. Pages
+	// can also be left out with "sitemap: false" in their front matter.
+	Exclude []string `json:"exclude"`
+}
+
+// Feed configures an RSS or Atom feed written after a build.
+type Feed struct {
+	// Out is the path the feed is written to, relative to the public directory.
+	Out string `json:"out"`
+
+	// Format is "rss" (the default) or "atom".
+	Format string `json:"format"`
+
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+
+	// Link is the URL of the site the feed is for. It defaults to the base URL.
+	Link string `json:"link"`
+
+	// Content is a directory of Markdown pages, or a glob like "content/blog/**/*.md",
+	// to make items from. Each page's front matter provides its title, date, summary
+	// and author, and its URL comes from the block which renders it. Like a block's in,
+	// it's relative to the working directory.
+	Content string `json:"content"`
+
+	// Data is a JSON or YAML file containing a list of items, each with a title, link,
+	// date and optionally a summary, content, id and author. It's used instead of
+	// Content.
+	Data string `json:"data"`
+
+	// Limit is the maximum number of items, newest first. Zero includes every item.
+	Limit int `json:"limit"`
+}
+
+// Bundle is a set of stylesheets or scripts which the bundle and inlineBundle functions
+// concatenate into one file.
+type Bundle struct {
+	// Type is "css" or "js". It defaults to the extension of Out.
+	Type string `json:"type"`
+
+	// Out is the path the bundle is written to, relative to the public directory. It
+	// defaults to /bundles/<name>.<type>.
+	Out string `json:"out"`
+
+	// Files are the paths of the bundled files, relative to the public directory.
+	Files []string `json:"files"`
+}
+
+// Assets configures the asset function.
+type Assets struct {
+	// Dir is the public directory asset paths are relative to, relative to the config
+	// file. It defaults to the -dir flag.
+	Dir string `json:"dir"`
+
+	// Fingerprint is how asset URLs are cache-busted in production: "filename" (the
+	// default) copies each asset to a file with its hash in the name, "query" adds the
+	// hash as a query string, and "none" leaves URLs unchanged.
+	Fingerprint string `json:"fingerprint"`
+
+	// CDN is prefixed to asset URLs in production.
+	CDN string `json:"cdn"`
+
+	// Manifest is the path, relative to Dir, the URL of every asset is written to in
+	// production. It defaults to "asset-manifest.json"; set it to "-" to skip it.
+	Manifest string `json:"manifest"`
+}
+
+// I18n configures the translation catalogs used by blocks with locales.
+type I18n struct {
+	// Dir is the directory containing a catalog for each locale, like fr.json or
+	// de.yaml, relative to the config file. It defaults to "locales".
+	Dir string `json:"dir"`
+
+	// Fallback is the locale used for messages missing from another locale's catalog.
+	// It defaults to "en".
+	Fallback string `json:"fallback"`
+
+	// Strict makes a missing message fail the render instead of just being reported.
+	Strict bool `json:"strict"`
+}
+
+// Function declares a template function implemented by an external command. The
+// command receives the function's arguments as a JSON array on stdin, and writes a JSON
+// object with a "result" and an optional list of "deps" to stdout.
+type Function struct {
+	Exec    []string `json:"exec"`
+	Timeout string   `json:"timeout"`
+}
+
+func (c *Config) UnmarshalJSON(by []byte) error {
+	if trimmed := bytes.TrimSpace(by); len(trimmed) > 0 && trimmed[0] == '[' {
+		*c = Config{}
+		return json.Unmarshal(trimmed, &c.Blocks)
+	}
+
+	type config Config
+	return json.Unmarshal(by, (*config)(c))
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jimmysawczuk/tmpl/config"
+	"github.com/jimmysawczuk/tmpl/pipe"
+	"github.com/jimmysawczuk/tmpl/tmpl"
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// feedItem is an entry in a feed, made from a Markdown page or a data file.
+type feedItem struct {
+	Title   string
+	Link    string
+	ID      string
+	Summary string
+	Content string
+	Author  string
+	Date    time.Time
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+	Content   *atomText   `xml:"content,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// validateFeed returns an error if the feed is missing required settings.
+func validateFeed(f config.Feed) error {
+	switch {
+	case f.Out == "":
+		return errors.New("missing out")
+	case f.Title == "":
+		return errors.New("missing title")
+	case f.Content == "" && f.Data == "":
+		return errors.New("missing content or data")
+	case f.Content != "" && f.Data != "":
+		return errors.New("content and data can't both be set")
+	}
+
+	switch f.Format {
+	case "", "rss", "atom":
+	default:
+		return errors.Errorf("unknown format %q", f.Format)
+	}
+
+	return nil
+}
+
+// writeFeed writes the feed into publicDir. Items from Markdown pages link to the
+// output of the pipe which renders them.
+func writeFeed(f config.Feed, baseURL string, publicDir string, pipes []*pipe.Pipe) error {
+	var items []feedItem
+	var err error
+	if f.Data != "" {
+		items, err = dataItems(f.Data, baseURL)
+	} else {
+		items, err = contentItems(f.Content, baseURL, publicDir, pipes)
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+
+	if f.Limit > 0 && len(items) > f.Limit {
+		items = items[:f.Limit]
+	}
+
+	link := f.Link
+	if link == "" {
+		link = baseURL
+	}
+
+	self := absoluteURL(baseURL, filepath.ToSlash(f.Out))
+	out := filepath.Join(publicDir, f.Out)
+
+	if f.Format == "atom" {
+		return writeXML(out, atomFeedFor(f, link, self, items))
+	}
+
+	return writeXML(out, rssFeedFor(f, link, self, items))
+}
+
+func rssFeedFor(f config.Feed, link string, self string, items []feedItem) rssFeed {
+	description := f.Description
+	if description == "" {
+		description = f.Title
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        link,
+			Description: description,
+			Self:        atomLink{Href: self, Rel: "self", Type: "application/rss+xml"},
+			Items:       []rssItem{},
+		},
+	}
+
+	if len(items) > 0 {
+		feed.Channel.LastBuildDate = items[0].Date.Format(time.RFC1123Z)
+	}
+
+	for _, item := range items {
+		description := item.Summary
+		if description == "" {
+			description = item.Content
+		}
+
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.Link, Value: item.ID},
+			PubDate:     item.Date.Format(time.RFC1123Z),
+			Description: description,
+		})
+	}
+
+	return feed
+}
+
+// atomFeedFor returns the Atom feed for items. The feed was last updated when its newest
+// item was; an empty feed uses the Unix epoch, so rebuilding it doesn't change it.
+func atomFeedFor(f config.Feed, link string, self string, items []feedItem) atomFeed {
+	updated := time.Unix(0, 0).UTC()
+	if len(items) > 0 {
+		updated = items[0].Date
+	}
+
+	feed := atomFeed{
+		Title: f.Title,
+		ID:    link,
+		Links: []atomLink{
+			{Href: link},
+			{Href: self, Rel: "self", Type: "application/atom+xml"},
+		},
+		Updated: updated.Format(time.RFC3339),
+		Entries: []atomEntry{},
+	}
+
+	if f.Author != "" {
+		feed.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, item := range items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Link:      atomLink{Href: item.Link},
+			Updated:   item.Date.Format(time.RFC3339),
+			Published: item.Date.Format(time.RFC3339),
+			Summary:   item.Summary,
+		}
+
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+// contentItems returns an item for each Markdown page matching content, a directory or
+// a glob. Drafts are skipped.
+func contentItems(content string, baseURL string, publicDir string, pipes []*pipe.Pipe) ([]feedItem, error) {
+	pattern := content
+	if !isGlob(pattern) {
+		pattern = filepath.Join(content, "**", "*.md")
+	}
+
+	_, files, err := glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "glob content (path: %s)", content)
+	}
+
+	items := []feedItem{}
+	for _, file := range files {
+		file, _ = filepath.Abs(file)
+
+		var renderer *pipe.Pipe
+		for _, p := range pipes {
+			if p.In == file && p.Layout != "" {
+				renderer = p
+				break
+			}
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read (path: %s)", file)
+		}
+
+		opts := tmplfunc.MarkdownOptions{}
+		if renderer != nil {
+			opts = renderer.Markdown
+		}
+
+		page, content, err := tmpl.ParsePage(src, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse page (path: %s)", file)
+		}
+
+		if draft, _ := page["draft"].(bool); draft {
+			continue
+		}
+
+		if _, ok := page["url"]; !ok && renderer != nil {
+			if u, ok := pageURL(publicDir, renderer.Out); ok {
+				page["url"] = u
+			}
+		}
+
+		if _, ok := page["content"]; !ok {
+			page["content"] = string(content)
+		}
+
+		if _, ok := page["title"]; !ok {
+			page["title"] = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+
+		if _, ok := page["date"]; !ok {
+			if t, err := lastModified(file, page); err == nil {
+				page["date"] = t
+			}
+		}
+
+		item, err := newFeedItem(page, baseURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "feed item (path: %s)", file)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// dataItems returns the items listed in the JSON or YAML file at p.
+func dataItems(p string, baseURL string) ([]feedItem, error) {
+	by, err := os.ReadFile(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read (path: %s)", p)
+	}
+
+	data := []map[string]interface{}{}
+	switch filepath.Ext(p) {
+	case ".json":
+		err = json.Unmarshal(by, &data)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(by, &data)
+	default:
+		return nil, errors.Errorf("data must be a .json, .yaml or .yml file (path: %s)", p)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "decode (path: %s)", p)
+	}
+
+	items := make([]feedItem, 0, len(data))
+	for i, d := range data {
+		item, err := newFeedItem(d, baseURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "feed item (path: %s, item: %d)", p, i+1)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// newFeedItem makes a feedItem from the fields in m: title, link or url, date, id,
+// summary or description, content and author.
+func newFeedItem(m map[string]interface{}, baseURL string) (feedItem, error) {
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			if v, ok := m[key].(string); ok && v != "" {
+				return v
+			}
+		}
+
+		return ""
+	}
+
+	item := feedItem{
+		Title:   str("title"),
+		Link:    str("link", "url"),
+		ID:      str("id", "guid"),
+		Summary: str("summary", "description"),
+		Content: str("content"),
+		Author:  str("author"),
+	}
+
+	if item.Title == "" {
+		return item, errors.New("missing title")
+	}
+
+	if item.Link == "" {
+		return item, errors.New("missing link; set a link or url, or render the page with a block")
+	}
+
+	item.Link = absoluteURL(baseURL, item.Link)
+	if item.ID == "" {
+		item.ID = item.Link
+	}
+
+	date, ok := m["date"]
+	if !ok {
+		return item, errors.New("missing date")
+	}
+
+	t, err := tmplfunc.ToTime(date)
+	if err != nil {
+		return item, errors.Wrap(err, "date")
+	}
+
+	item.Date = t
+
+	return item, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmysawczuk/tmpl/config"
+)
+
+func TestValidateFeed(t *testing.T) {
+	tests := []struct {
+		f   config.Feed
+		err bool
+	}{
+		{config.Feed{Out: "feed.xml", Title: "Blog", Content: "content"}, false},
+		{config.Feed{Out: "feed.xml", Title: "Blog", Data: "items.yaml", Format: "atom"}, false},
+		{config.Feed{Title: "Blog", Content: "content"}, true},
+		{config.Feed{Out: "feed.xml", Content: "content"}, true},
+		{config.Feed{Out: "feed.xml", Title: "Blog"}, true},
+		{config.Feed{Out: "feed.xml", Title: "Blog", Content: "content", Data: "items.yaml"}, true},
+		{config.Feed{Out: "feed.xml", Title: "Blog", Content: "content", Format: "json"}, true},
+	}
+
+	for _, test := range tests {
+		if err := validateFeed(test.f); (err != nil) != test.err {
+			t.Errorf("validateFeed(%+v) = %v, want error: %v", test.f, err, test.err)
+		}
+	}
+}
+
+func TestWriteFeedData(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"items.yaml": `- title: One
+  url: /one.html
+  date: 2020-01-01
+- title: Three
+  link: https://elsewhere.com/three
+  id: three
+  date: 2020-03-01T12:00:00Z
+  summary: The third.
+- title: Two
+  url: /two.html
+  date: 2020-02-01
+  content: <p>Two</p>
+`,
+	})
+
+	f := config.Feed{Out: "feed.xml", Title: "Blog", Data: filepath.Join(dir, "items.yaml"), Limit: 2}
+	if err := writeFeed(f, "https://example.com", dir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	by, err := os.ReadFile(filepath.Join(dir, "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rss rssFeed
+	if err := xml.Unmarshal(by, &rss); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []rssItem{
+		{
+			Title:       "Three",
+			Link:        "https://elsewhere.com/three",
+			GUID:        rssGUID{Value: "three"},
+			PubDate:     "Sun, 01 Mar 2020 12:00:00 +0000",
+			Description: "The third.",
+		},
+		{
+			Title:       "Two",
+			Link:        "https://example.com/two.html",
+			GUID:        rssGUID{IsPermaLink: true, Value: "https://example.com/two.html"},
+			PubDate:     "Sat, 01 Feb 2020 00:00:00 +0000",
+			Description: "<p>Two</p>",
+		},
+	}
+
+	if !reflect.DeepEqual(rss.Channel.Items, want) {
+		t.Errorf("items = %+v, want %+v", rss.Channel.Items, want)
+	}
+
+	if rss.Channel.Description != "Blog" || rss.Channel.LastBuildDate != want[0].PubDate {
+		t.Errorf("channel = %+v", rss.Channel)
+	}
+
+	if self := `href="https://example.com/feed.xml" rel="self"`; !strings.Contains(string(by), self) {
+		t.Errorf("feed = %s, want a link with %s", by, self)
+	}
+}
+
+func TestWriteFeedContent(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"layout.html":           "{{ .Content }}",
+		"content/blog/first.md": "---\ntitle: First\ndate: 2020-01-01\nauthor: Jo\n---\nHello",
+		"content/blog/draft.md": "---\ntitle: Draft\ndate: 2020-06-01\ndraft: true\n---\nWIP",
+		"content/blog/ext.md":   "---\ndate: 2020-02-01\nurl: https://elsewhere.com/ext\n---\nExt",
+	})
+
+	pipes := pagePipes(t, dir, "blog/first")
+
+	f := config.Feed{Out: "blog/atom.xml", Format: "atom", Title: "Blog", Author: "Site", Content: filepath.Join(dir, "content", "blog")}
+	if err := writeFeed(f, "https://example.com", filepath.Join(dir, "public"), pipes); err != nil {
+		t.Fatal(err)
+	}
+
+	by, err := os.ReadFile(filepath.Join(dir, "public", "blog", "atom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var atom atomFeed
+	if err := xml.Unmarshal(by, &atom); err != nil {
+		t.Fatal(err)
+	}
+
+	if atom.Updated != "2020-02-01T00:00:00Z" {
+		t.Errorf("updated = %q, want %q", atom.Updated, "2020-02-01T00:00:00Z")
+	}
+
+	if len(atom.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(atom.Entries))
+	}
+
+	ext, first := atom.Entries[0], atom.Entries[1]
+	if ext.Title != "ext" || ext.Link.Href != "https://elsewhere.com/ext" {
+		t.Errorf("entry = %+v, want the title from the file name and the link from its url", ext)
+	}
+
+	if first.Title != "First" || first.Link.Href != "https://example.com/blog/first.html" || first.Author == nil || first.Author.Name != "Jo" {
+		t.Errorf("entry = %+v, want the link to the rendered page", first)
+	}
+
+	if first.Content == nil || first.Content.Value != "<p>Hello</p>\n" {
+		t.Errorf("entry content = %+v, want the page's HTML", first.Content)
+	}
+}
+
+func TestWriteFeedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"title", "- url: /a.html\n  date: 2020-01-01\n"},
+		{"link", "- title: A\n  date: 2020-01-01\n"},
+		{"date", "- title: A\n  url: /a.html\n"},
+		{"bad date", "- title: A\n  url: /a.html\n  date: soon\n"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"items.yml": test.data})
+
+		f := config.Feed{Out: "feed.xml", Title: "Blog", Data: filepath.Join(dir, "items.yml")}
+		if err := writeFeed(f, "https://example.com", dir, nil); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestAtomFeedForEmpty(t *testing.T) {
+	f := config.Feed{Title: "Blog"}
+
+	feed := atomFeedFor(f, "https://example.com", "https://example.com/atom.xml", nil)
+	if feed.Updated != "1970-01-01T00:00:00Z" {
+		t.Errorf("updated = %q, want %q", feed.Updated, "1970-01-01T00:00:00Z")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+	"github.com/pkg/errors"
+)
+
+// runFuncs lists every function available in templates, including those declared in
+// the config file, if there is one:
+//
+//	tmpl funcs [-json] [-f config] [name...]
+func runFuncs(args []string) error {
+	var asJSON bool
+	path := configFile
+
+	fs := flag.NewFlagSet("funcs", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  tmpl funcs [options] [name...]\n\n")
+		fs.PrintDefaults()
+	}
+
+	fs.BoolVar(&asJSON, "json", false, "output as JSON")
+	fs.StringVar(&path, "f", path, "path to tmpl config file, for its functions")
+
+	if err := fs.Parse(args); err != nil {
+		return errors.Wrap(err, "parse flags")
+	}
+
+	funcs, err := availableFuncs(path)
+	if err != nil {
+		return err
+	}
+
+	if names := fs.Args(); len(names) > 0 {
+		byName := map[string]tmplfunc.Func{}
+		for _, f := range funcs {
+			byName[f.Name] = f
+		}
+
+		funcs = funcs[:0]
+		for _, name := range names {
+			f, ok := byName[name]
+			if !ok {
+				return errors.Errorf("function not found: %s", name)
+			}
+
+			funcs = append(funcs, f)
+		}
+	}
+
+	if asJSON {
+		type jsonFunc struct {
+			Name    string `json:"name"`
+			Doc     string `json:"doc"`
+			Example string `json:"example,omitempty"`
+		}
+
+		list := make([]jsonFunc, len(funcs))
+		for i, f := range funcs {
+			list[i] = jsonFunc{Name: f.Name, Doc: f.Meta.Doc, Example: f.Meta.Example}
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		return enc.Encode(list)
+	}
+
+	for i, f := range funcs {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Println(f.Name)
+		if f.Meta.Doc != "" {
+			fmt.Printf("    %s\n", f.Meta.Doc)
+		}
+		if f.Meta.Example != "" {
+			fmt.Printf("\n    %s\n", strings.ReplaceAll(f.Meta.Example, "\n", "\n    "))
+		}
+	}
+
+	return nil
+}
+
+// availableFuncs returns the registered functions, sorted by name, along with the
+// functions declared in the config file at path, which replace registered functions of
+// the same name as they do in templates. A missing config file is skipped.
+func availableFuncs(path string) ([]tmplfunc.Func, error) {
+	funcs := tmplfunc.Registered()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return funcs, nil
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	projectDir, _ := filepath.Abs(filepath.Dir(path))
+	commands, err := commandsFor(projectDir, cfg.Functions)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := funcs[:0]
+	for _, f := range funcs {
+		if _, ok := commands[f.Name]; !ok {
+			tbr = append(tbr, f)
+		}
+	}
+
+	for name, c := range commands {
+		tbr = append(tbr, tmplfunc.Func{
+			Name: name,
+			Meta: tmplfunc.Meta{
+				Doc: fmt.Sprintf("%s runs %s, as declared in the config file's functions.", name, strings.Join(c.Exec, " ")),
+			},
+		})
+	}
+
+	sort.Slice(tbr, func(i, j int) bool {
+		return tbr[i].Name < tbr[j].Name
+	})
+
+	return tbr, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+)
+
+func TestAvailableFuncs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"tmpl.config.json": `{"functions": {
+			"zzShout": {"exec": ["./shout.sh", "-x"]},
+			"upper": {"exec": ["tr", "a-z", "A-Z"]}
+		}}`,
+		"empty.json": `{"functions": {"broken": {"exec": []}}}`,
+	})
+
+	funcs, err := availableFuncs(filepath.Join(dir, "tmpl.config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := map[string]tmplfunc.Func{}
+	for i, f := range funcs {
+		if _, ok := byName[f.Name]; ok {
+			t.Errorf("%s is listed twice", f.Name)
+		}
+
+		if i > 0 && funcs[i-1].Name > f.Name {
+			t.Errorf("%s is listed after %s", f.Name, funcs[i-1].Name)
+		}
+
+		byName[f.Name] = f
+	}
+
+	if f, ok := byName["zzShout"]; !ok || !strings.Contains(f.Meta.Doc, "./shout.sh -x") {
+		t.Errorf("zzShout = %+v, want the config's command", f)
+	}
+
+	if f := byName["upper"]; !strings.Contains(f.Meta.Doc, "tr a-z A-Z") {
+		t.Errorf("upper = %+v, want the config's command to replace the built-in", f)
+	}
+
+	if _, ok := byName["markdown"]; !ok {
+		t.Errorf("markdown is missing")
+	}
+
+	builtins, err := availableFuncs(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("without a config file: %s", err)
+	}
+
+	if len(builtins) != len(tmplfunc.Registered()) {
+		t.Errorf("without a config file: got %d functions, want %d", len(builtins), len(tmplfunc.Registered()))
+	}
+
+	if _, err := availableFuncs(filepath.Join(dir, "empty.json")); err == nil {
+		t.Errorf("with a function without a command: expected an error")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// isGlob reports whether pattern contains any glob metacharacters.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// glob returns the files matching pattern, sorted, along with the directory the pattern
+// is rooted at. Patterns are matched like filepath.Match, except that a "**" path
+// element matches any number of directories.
+func glob(pattern string) (string, []string, error) {
+	root, elems, err := splitGlob(pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	matches := []string{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		if matchElems(elems, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "walk (path: %s)", root)
+	}
+
+	sort.Strings(matches)
+	return root, matches, nil
+}
+
+// globMatcher returns a function which reports whether a path, relative to the working
+// directory or absolute, matches pattern, for checking files created after glob ran.
+func globMatcher(pattern string) (func(string) bool, error) {
+	root, elems, err := splitGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "filepath: abs (path: %s)", root)
+	}
+
+	return func(p string) bool {
+		p, err := filepath.Abs(p)
+		if err != nil {
+			return false
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+
+		return matchElems(elems, strings.Split(filepath.ToSlash(rel), "/"))
+	}, nil
+}
+
+// splitGlob splits pattern into the directory it's rooted at, which is every element
+// before the first one with a metacharacter, and the pattern's remaining elements.
+func splitGlob(pattern string) (string, []string, error) {
+	parts := strings.Split(filepath.ToSlash(pattern), "/")
+
+	i := 0
+	for i < len(parts)-1 && !isGlob(parts[i]) {
+		i++
+	}
+
+	root := filepath.FromSlash(strings.Join(parts[:i], "/"))
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+
+	elems := parts[i:]
+	for _, elem := range elems {
+		if _, err := path.Match(elem, ""); err != nil {
+			return "", nil, errors.Wrapf(err, "bad pattern (pattern: %s)", pattern)
+		}
+	}
+
+	return root, elems, nil
+}
+
+// matchElems reports whether the path elements in name match the pattern elements.
+func matchElems(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchElems(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+
+	return matchElems(pattern[1:], name[1:])
+}
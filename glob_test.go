@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"index.md", "about.txt", "blog/a.md", "blog/2020/b.md", "blog/2020/c.txt"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		pattern string
+		root    string
+		want    []string
+	}{
+		{"*.md", "", []string{"index.md"}},
+		{"**/*.md", "", []string{"blog/2020/b.md", "blog/a.md", "index.md"}},
+		{"blog/*.md", "blog", []string{"blog/a.md"}},
+		{"blog/**/*.txt", "blog", []string{"blog/2020/c.txt"}},
+		{"blog/*/?.md", "blog", []string{"blog/2020/b.md"}},
+		{"*.html", "", []string{}},
+	}
+
+	for _, test := range tests {
+		root, got, err := glob(filepath.Join(dir, test.pattern))
+		if err != nil {
+			t.Errorf("glob(%q): %s", test.pattern, err)
+			continue
+		}
+
+		if want := filepath.Join(dir, test.root); root != want {
+			t.Errorf("glob(%q) root = %q, want %q", test.pattern, root, want)
+		}
+
+		want := []string{}
+		for _, name := range test.want {
+			want = append(want, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("glob(%q) = %q, want %q", test.pattern, got, want)
+		}
+	}
+
+	if _, _, err := glob(filepath.Join(dir, "[*.md")); err == nil {
+		t.Errorf("glob(%q): expected an error", "[*.md")
+	}
+}
+
+func TestGlobMatcher(t *testing.T) {
+	dir := t.TempDir()
+
+	match, err := globMatcher(filepath.Join(dir, "content", "**", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"content/index.md", true},
+		{"content/blog/2020/post.md", true},
+		{"content/blog/post.txt", false},
+		{"content", false},
+		{"index.md", false},
+		{"other/content/index.md", false},
+	}
+
+	for _, test := range tests {
+		if got := match(filepath.Join(dir, filepath.FromSlash(test.path))); got != test.want {
+			t.Errorf("match(%q) = %v, want %v", test.path, got, test.want)
+		}
+	}
+}
@@ -1,17 +1,23 @@
 module github.com/jimmysawczuk/tmpl
 
-go 1.25
+go 1.25.0
 
 require (
-	github.com/fsnotify/fsnotify v1.4.9
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/pelletier/go-toml/v2 v2.2.2
 	github.com/pkg/errors v0.9.1
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
-	github.com/tdewolff/minify v2.3.6+incompatible
-	github.com/yuin/goldmark v1.4.4
+	github.com/tdewolff/minify/v2 v2.20.37
+	github.com/tdewolff/parse/v2 v2.7.15
+	github.com/yuin/goldmark v1.4.15
+	github.com/yuin/goldmark-highlighting/v2 v2.0.0-20230729083705-37449abec8cc
+	golang.org/x/text v0.38.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/tdewolff/parse v2.3.4+incompatible // indirect
-	github.com/tdewolff/test v1.0.6 // indirect
-	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
 )
@@ -10,11 +10,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/jimmysawczuk/tmpl/config"
 	"github.com/jimmysawczuk/tmpl/pipe"
 	"github.com/jimmysawczuk/tmpl/tmpl"
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,7 @@ var (
 	baseDir     string
 	configFile  string
 	showVersion bool
+	sandbox     bool
 	runCommand  []string
 )
 
@@ -47,7 +50,9 @@ func init() {
 		fmt.Printf("  rev:   %s\n\n", rev)
 
 		fmt.Printf("Usage:\n")
-		fmt.Printf("  tmpl [options] [-- command]\n\n")
+		fmt.Printf("  tmpl [options] [-- command]\n")
+		fmt.Printf("  tmpl render [file|-] [-o out|-] [options]\n")
+		fmt.Printf("  tmpl funcs [-json] [name...]\n\n")
 
 		flag.PrintDefaults()
 	}
@@ -58,9 +63,25 @@ func init() {
 	flag.IntVar(&port, "p", 8080, "port to listen on in serve mode")
 	flag.StringVar(&baseDir, "dir", ".", "public dir")
 	flag.BoolVar(&showVersion, "v", false, "show version information")
+	flag.BoolVar(&sandbox, "sandbox", false, "restrict file access in every block to the config file's directory")
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "render":
+			if err := runRender(os.Args[2:]); err != nil {
+				log.Fatal(err.Error())
+			}
+			return
+		case "funcs":
+			if err := runFuncs(os.Args[2:]); err != nil {
+				log.Fatal(err.Error())
+			}
+			return
+		}
+	}
+
 	flag.Parse()
 
 	if showVersion {
@@ -81,14 +102,9 @@ func main() {
 }
 
 func run() error {
-	fp, err := os.Open(configFile)
+	cfg, err := loadConfig(configFile)
 	if err != nil {
-		return errors.Wrapf(err, "open config file (path: %s)", configFile)
-	}
-
-	var blocks []config.Block
-	if err := json.NewDecoder(fp).Decode(&blocks); err != nil {
-		return errors.Wrap(err, "json: decode config file")
+		return err
 	}
 
 	watcher, err := pipe.New(watchMode)
@@ -102,62 +118,253 @@ func run() error {
 		mode = tmpl.ModeLocal
 	}
 
+	projectDir, _ := filepath.Abs(filepath.Dir(configFile))
+
+	commands, err := commandsFor(projectDir, cfg.Functions)
+	if err != nil {
+		return err
+	}
+
+	localesDir, err := localesDirFor(projectDir, cfg.I18n)
+	if err != nil {
+		return err
+	}
+
+	fallback := cfg.I18n.Fallback
+	if fallback == "" {
+		fallback = "en"
+	}
+
+	assets := tmplfunc.AssetOptions{
+		Fingerprint: cfg.Assets.Fingerprint,
+		CDN:         cfg.Assets.CDN,
+		Manifest:    tmplfunc.NewAssetManifest(),
+		Bundles:     map[string]tmplfunc.Bundle{},
+	}
+
+	for name, b := range cfg.Bundles {
+		if len(b.Files) == 0 {
+			return errors.Errorf("bundle %s has no files", name)
+		}
+
+		bundle, err := tmplfunc.Bundle(b).Resolve(name)
+		if err != nil {
+			return err
+		}
+
+		assets.Bundles[name] = bundle
+	}
+
+	switch assets.Fingerprint {
+	case "", tmplfunc.FingerprintFilename, tmplfunc.FingerprintQuery, tmplfunc.FingerprintNone:
+	default:
+		return errors.Errorf("unknown asset fingerprint style %q", assets.Fingerprint)
+	}
+
+	if cfg.Assets.Dir != "" && !filepath.IsAbs(cfg.Assets.Dir) {
+		assets.Dir = filepath.Join(projectDir, cfg.Assets.Dir)
+	} else if cfg.Assets.Dir != "" {
+		assets.Dir = cfg.Assets.Dir
+	} else {
+		assets.Dir, _ = filepath.Abs(baseDir)
+	}
+
+	if (cfg.Sitemap != nil || len(cfg.Feeds) > 0) && cfg.BaseURL == "" {
+		return errors.New("baseURL is required for the sitemap and feeds")
+	}
+
+	if cfg.Sitemap != nil {
+		if err := validateSitemap(*cfg.Sitemap); err != nil {
+			return errors.Wrap(err, "sitemap")
+		}
+	}
+
+	for i, f := range cfg.Feeds {
+		if err := validateFeed(f); err != nil {
+			return errors.Wrapf(err, "feed %d", i+1)
+		}
+	}
+
+	cspHashes := tmplfunc.NewCSPHashes()
+
 	pipes := []*pipe.Pipe{}
 
-	for i, b := range blocks {
+	for i, b := range cfg.Blocks {
+		locales := b.Options.Locales
+		if len(locales) == 0 {
+			locales = []string{""}
+		}
+
+		inputs, root := []string{b.In}, ""
+		var match func(string) bool
+		if isGlob(b.In) {
+			root, inputs, err = glob(b.In)
+			if err != nil {
+				return errors.Wrapf(err, "glob input (block: %d)", i+1)
+			}
+
+			if match, err = globMatcher(b.In); err != nil {
+				return errors.Wrapf(err, "glob input (block: %d)", i+1)
+			}
+		}
 
-		pipe := &pipe.Pipe{
-			Format: b.Format,
-			Mode:   mode,
+		switch b.Options.LineEndings {
+		case "", tmpl.LineEndingsLF, tmpl.LineEndingsCRLF:
+		default:
+			return errors.Errorf("unknown line endings %q (block: %d)", b.Options.LineEndings, i+1)
+		}
+
+		layout := ""
+		if b.Layout != "" {
+			layout, _ = filepath.Abs(b.Layout)
+		}
 
-			Minify: b.Options.Minify,
-			Env:    b.Options.Env,
-			Delims: b.Options.Delims,
+		outs := make([]string, len(locales))
+		for j, locale := range locales {
+			outs[j] = b.Out
+			if locale != "" {
+				outs[j], err = localeOutput(b.Out, locale)
+				if err != nil {
+					return errors.Wrapf(err, "output path (block: %d, locale: %s)", i+1, locale)
+				}
+			}
 		}
 
-		pipe.In, _ = filepath.Abs(b.In)
+		// newPipes returns the block's pipes for an input, one per locale. It's also
+		// called by the watcher for files created later which match the block's glob.
+		newPipes := func(in string) ([]*pipe.Pipe, error) {
+			tbr := make([]*pipe.Pipe, 0, len(locales))
+			for j, locale := range locales {
+				pipe := &pipe.Pipe{
+					BaseDir: projectDir,
+					Layout:  layout,
+					Format:  b.Format,
+					Mode:    mode,
+
+					Minify:          b.Options.Minify,
+					MinifyOptions:   tmpl.MinifyOptions(b.Options.MinifyOptions),
+					Indent:          string(b.Options.Indent),
+					Canonical:       b.Options.Canonical,
+					Schema:          b.Options.Schema,
+					SortKeys:        b.Options.SortKeys,
+					TrailingNewline: b.Options.TrailingNewline,
+
+					TrimTrailingWhitespace: b.Options.TrimTrailingWhitespace,
+					CollapseBlankLines:     b.Options.CollapseBlankLines,
+					LineEndings:            b.Options.LineEndings,
+					Header:                 b.Options.Header,
+
+					Env:      b.Options.Env,
+					Delims:   b.Options.Delims,
+					Params:   b.Options.Params,
+					Commands: commands,
+
+					DisableFuncs: b.Options.DisableFuncs,
+					ErrorPage:    b.Options.ErrorPage,
+
+					Markdown: tmplfunc.MarkdownOptions(b.Options.Markdown),
+					Assets:   assets,
+
+					CSPHashes: cspHashes,
+
+					Sandbox:      sandbox || b.Options.Sandbox,
+					SandboxAllow: b.Options.SandboxAllow,
+
+					Locale:         locale,
+					LocalesDir:     localesDir,
+					FallbackLocale: fallback,
+					StrictLocales:  cfg.I18n.Strict,
+				}
+
+				if locale == "" {
+					pipe.Locale = fallback
+				}
+
+				pipe.In, _ = filepath.Abs(in)
 
-		ostat, err := os.Stat(b.Out)
-		if err == nil {
-			if ostat.IsDir() {
-				outpath := filepath.Join(b.Out, filepath.Base(b.In))
+				var err error
+				name := outputName(filepath.Base(in), layout)
+				if root != "" {
+					// Globbed inputs keep their path relative to the pattern's root.
+					rel, _ := filepath.Rel(root, in)
+					if filepath.IsAbs(in) {
+						absRoot, _ := filepath.Abs(root)
+						rel, _ = filepath.Rel(absRoot, in)
+					}
 
-				_, err := os.Stat(outpath)
-				if err == nil || os.IsNotExist(err) {
-					pipe.Out, _ = filepath.Abs(outpath)
+					name = outputName(rel, layout)
+					pipe.Out, err = outputPath(filepath.Join(outs[j], name), filepath.Base(name))
 				} else {
-					return errors.Wrapf(err, "stat output path (path: %s, block: %d)", outpath, i+1)
+					pipe.Out, err = outputPath(outs[j], name)
+				}
+				if err != nil {
+					return nil, err
 				}
 
-				pipe.Out, _ = filepath.Abs(filepath.Join(b.Out, filepath.Base(b.In)))
-			} else {
-				pipe.Out, _ = filepath.Abs(b.Out)
-			}
-		} else if os.IsNotExist(err) {
-			if err := os.MkdirAll(filepath.Dir(b.Out), 0o755); err != nil {
-				return errors.Wrapf(err, "mkdir (path: %s, block: %d)", filepath.Dir(b.Out), i+1)
+				tbr = append(tbr, pipe)
 			}
 
-			pipe.Out, _ = filepath.Abs(b.Out)
-		} else {
-			return errors.Wrapf(err, "stat (output: %s, block: %d)", b.Out, i+1)
+			return tbr, nil
 		}
 
-		if err := watcher.AddPipe(pipe); err != nil {
-			log.Printf("couldn't watch path %s: %s", pipe.In, err)
+		for _, in := range inputs {
+			ps, err := newPipes(in)
+			if err != nil {
+				return errors.Wrapf(err, "output path (block: %d)", i+1)
+			}
+
+			for _, pipe := range ps {
+				if err := watcher.AddPipe(pipe); err != nil {
+					log.Printf("couldn't watch path %s: %s", pipe.In, err)
+				}
+
+				pipes = append(pipes, pipe)
+			}
 		}
 
-		pipes = append(pipes, pipe)
+		if match != nil {
+			if err := watcher.AddGlob(root, match, newPipes); err != nil {
+				log.Printf("couldn't watch path %s: %s", root, err)
+			}
+		}
 	}
 
 	for _, pipe := range pipes {
 		if err := pipe.Run(); err != nil {
-			return errors.Wrapf(err, "run pipeline (path: %s)", pipe.In)
+			if !watchMode {
+				return errors.Wrapf(err, "run pipeline (path: %s)", pipe.In)
+			}
+
+			// In watch mode, keep going so a fix to the template triggers a rebuild.
+			log.Printf("%s", errors.Wrapf(err, "run pipeline (path: %s)", pipe.In))
 		}
 
 		pipe.AttachRefs(watcher)
 	}
 
+	if mode == tmpl.ModeProduction && cfg.Assets.Manifest != "-" && assets.Manifest.Len() > 0 {
+		manifest := cfg.Assets.Manifest
+		if manifest == "" {
+			manifest = "asset-manifest.json"
+		}
+
+		if err := assets.Manifest.WriteFile(filepath.Join(assets.Dir, manifest)); err != nil {
+			return errors.Wrap(err, "write asset manifest")
+		}
+	}
+
+	if cfg.Sitemap != nil {
+		if err := writeSitemap(*cfg.Sitemap, cfg.BaseURL, assets.Dir, pipes); err != nil {
+			return errors.Wrap(err, "write sitemap")
+		}
+	}
+
+	for _, f := range cfg.Feeds {
+		if err := writeFeed(f, cfg.BaseURL, assets.Dir, pipes); err != nil {
+			return errors.Wrapf(err, "write feed (path: %s)", f.Out)
+		}
+	}
+
 	var cmd *exec.Cmd
 	if len(runCommand) > 0 {
 		cmd = exec.Command(runCommand[0], runCommand[1:]...)
@@ -189,6 +396,135 @@ func run() error {
 	return nil
 }
 
+// loadConfig reads and decodes the config file at path.
+func loadConfig(path string) (config.Config, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return config.Config{}, errors.Wrapf(err, "open config file (path: %s)", path)
+	}
+	defer fp.Close()
+
+	var cfg config.Config
+	if err := json.NewDecoder(fp).Decode(&cfg); err != nil {
+		return config.Config{}, errors.Wrap(err, "json: decode config file")
+	}
+
+	return cfg, nil
+}
+
+// commandsFor returns the commands for the functions declared in the config file, which
+// run in projectDir.
+func commandsFor(projectDir string, functions map[string]config.Function) (map[string]tmplfunc.Command, error) {
+	commands := map[string]tmplfunc.Command{}
+	for name, f := range functions {
+		if len(f.Exec) == 0 {
+			return nil, errors.Errorf("function %s has no command", name)
+		}
+
+		c := tmplfunc.Command{
+			Exec: f.Exec,
+			Dir:  projectDir,
+		}
+
+		if f.Timeout != "" {
+			d, err := time.ParseDuration(f.Timeout)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse timeout (function: %s)", name)
+			}
+
+			c.Timeout = d
+		}
+
+		commands[name] = c
+	}
+
+	return commands, nil
+}
+
+// localesDirFor returns the absolute path of the translation catalogs directory, or an
+// empty string if it doesn't exist. Strict mode needs catalogs to check messages
+// against, so it's an error for the directory to be missing then.
+func localesDirFor(projectDir string, c config.I18n) (string, error) {
+	dir := c.Dir
+	if dir == "" {
+		dir = "locales"
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectDir, dir)
+	}
+
+	if stat, err := os.Stat(dir); err != nil || !stat.IsDir() {
+		if c.Strict {
+			return "", errors.Errorf("i18n: strict mode needs a locales directory (path: %s)", dir)
+		}
+
+		return "", nil
+	}
+
+	return dir, nil
+}
+
+// localeOutput executes out, a block's out path, as a template with the provided
+// locale, like "{{ .Locale }}/index.html".
+func localeOutput(out string, locale string) (string, error) {
+	t, err := template.New("out").Parse(out)
+	if err != nil {
+		return "", errors.Wrap(err, "parse")
+	}
+
+	buf := strings.Builder{}
+	if err := t.Execute(&buf, struct{ Locale string }{locale}); err != nil {
+		return "", errors.Wrap(err, "execute")
+	}
+
+	if buf.String() == out {
+		return "", errors.Errorf("out path doesn't depend on the locale (path: %s)", out)
+	}
+
+	return buf.String(), nil
+}
+
+// outputName returns the name of the file written for the input name. Markdown pages
+// rendered into a layout are written as .html files.
+func outputName(name string, layout string) string {
+	ext := filepath.Ext(name)
+	if layout == "" || ext != ".md" && ext != ".markdown" {
+		return name
+	}
+
+	return strings.TrimSuffix(name, ext) + ".html"
+}
+
+// outputPath returns the absolute path a block writes to, creating its directory if
+// needed. If out is an existing directory, the block writes to a file there with the
+// provided name.
+func outputPath(out string, name string) (string, error) {
+	ostat, err := os.Stat(out)
+	if err == nil {
+		if ostat.IsDir() {
+			outpath := filepath.Join(out, name)
+
+			_, err := os.Stat(outpath)
+			if err != nil && !os.IsNotExist(err) {
+				return "", errors.Wrapf(err, "stat output path (path: %s)", outpath)
+			}
+
+			return filepath.Abs(outpath)
+		}
+
+		return filepath.Abs(out)
+	} else if os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return "", errors.Wrapf(err, "mkdir (path: %s)", filepath.Dir(out))
+		}
+
+		return filepath.Abs(out)
+	}
+
+	return "", errors.Wrapf(err, "stat (output: %s)", out)
+}
+
 func startServer(port int, watcherCh chan string) {
 	log.Printf("starting server on http://localhost:%d", port)
 
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jimmysawczuk/tmpl/config"
+)
+
+func TestLocalesDirFor(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "locales"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		c    config.I18n
+		want string
+		err  bool
+	}{
+		{"default", config.I18n{}, filepath.Join(dir, "locales"), false},
+		{"default strict", config.I18n{Strict: true}, filepath.Join(dir, "locales"), false},
+		{"missing", config.I18n{Dir: "i18n"}, "", false},
+		{"missing strict", config.I18n{Dir: "i18n", Strict: true}, "", true},
+		{"absolute", config.I18n{Dir: filepath.Join(dir, "locales")}, filepath.Join(dir, "locales"), false},
+	}
+
+	for _, test := range tests {
+		got, err := localesDirFor(dir, test.c)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCommandsFor(t *testing.T) {
+	commands, err := commandsFor("/site", map[string]config.Function{
+		"slug": {Exec: []string{"./slug.sh"}, Timeout: "5s"},
+		"date": {Exec: []string{"date"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := commands["slug"]; c.Dir != "/site" || c.Timeout != 5*time.Second || c.Exec[0] != "./slug.sh" {
+		t.Errorf("slug = %+v", c)
+	}
+
+	if c := commands["date"]; c.Timeout != 0 {
+		t.Errorf("date = %+v, want the default timeout", c)
+	}
+
+	tests := map[string]config.Function{
+		"no command":  {},
+		"empty":       {Exec: []string{}},
+		"bad timeout": {Exec: []string{"date"}, Timeout: "soon"},
+	}
+
+	for name, f := range tests {
+		if _, err := commandsFor("/site", map[string]config.Function{"f": f}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestOutputName(t *testing.T) {
+	tests := []struct {
+		name, layout, want string
+	}{
+		{"index.tmpl", "", "index.tmpl"},
+		{"post.md", "", "post.md"},
+		{"post.md", "layout.html", "post.html"},
+		{filepath.Join("blog", "post.markdown"), "layout.html", filepath.Join("blog", "post.html")},
+		{"notes.txt", "layout.html", "notes.txt"},
+	}
+
+	for _, test := range tests {
+		if got := outputName(test.name, test.layout); got != test.want {
+			t.Errorf("outputName(%q, %q) = %q, want %q", test.name, test.layout, got, test.want)
+		}
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := outputPath(dir, "post.html")
+	if want := filepath.Join(dir, "post.html"); err != nil || got != want {
+		t.Errorf("outputPath(dir) = %q, %v, want %q", got, err, want)
+	}
+
+	out := filepath.Join(dir, "public", "blog", "post.html")
+	got, err = outputPath(out, "post.html")
+	if err != nil || got != out {
+		t.Errorf("outputPath(new file) = %q, %v, want %q", got, err, out)
+	}
+
+	if stat, err := os.Stat(filepath.Dir(out)); err != nil || !stat.IsDir() {
+		t.Errorf("outputPath(new file) didn't create its directory: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package pipe
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/jimmysawczuk/tmpl/tmpl"
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+)
+
+var errorPageTmpl = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>tmpl: error</title>
+<style>body{font-family:sans-serif;margin:2em}pre{background:#fee;border:1px solid #c00;padding:1em;white-space:pre-wrap}</style>
+</head>
+<body>
+<h1>tmpl couldn't render this page</h1>
+<p>Input: <code>{{ .In }}</code></p>
+<pre>{{ .Err }}</pre>
+{{ .Autoreload }}
+</body>
+</html>
+`))
+
+// writeErrorPage writes an HTML page describing err to w. The page includes the
+// autoreload snippet so it's replaced as soon as the template is fixed.
+func writeErrorPage(w io.Writer, in string, err error) error {
+	return errorPageTmpl.Execute(w, struct {
+		In         string
+		Err        string
+		Autoreload template.HTML
+	}{
+		In:         in,
+		Err:        err.Error(),
+		Autoreload: tmplfunc.Autoreload(tmpl.New().WithMode(tmpl.ModeLocal))(),
+	})
+}
@@ -1,11 +1,14 @@
 package pipe
 
 import (
+	html "html/template"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/jimmysawczuk/tmpl/tmpl"
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 	"github.com/pkg/errors"
 )
 
@@ -14,15 +17,60 @@ type Pipe struct {
 	Out     string
 	BaseDir string
 
+	// Layout is the template executed for a Markdown page. If set, In is parsed with
+	// tmpl.ParsePage and made available to the layout as .Page and .Content.
+	Layout string
+
 	Format string
 	Mode   tmpl.Mode
 
-	Minify bool
-	Env    map[string]string
-	Delims [2]string
-	Params map[string]interface{}
+	Minify          bool
+	MinifyOptions   tmpl.MinifyOptions
+	Indent          string
+	Canonical       bool
+	Schema          string
+	SortKeys        bool
+	TrailingNewline bool
+
+	// TrimTrailingWhitespace, CollapseBlankLines, LineEndings and Header clean up plain
+	// text output; see tmpl.TextOptions.
+	TrimTrailingWhitespace bool
+	CollapseBlankLines     bool
+	LineEndings            string
+	Header                 bool
+
+	Env       map[string]string
+	Delims    [2]string
+	Params    map[string]interface{}
+	Commands  map[string]tmplfunc.Command
+	ErrorPage bool
+
+	Sandbox      bool
+	SandboxAllow []string
+
+	DisableFuncs []string
+
+	Markdown tmplfunc.MarkdownOptions
+	Assets   tmplfunc.AssetOptions
+
+	// CSPHashes collects the hashes of inline scripts and styles in html output.
+	CSPHashes *tmplfunc.CSPHashes
+
+	// Locale is the locale the template is rendered for. Messages for the t function are
+	// loaded from the catalogs in LocalesDir on every run.
+	Locale         string
+	LocalesDir     string
+	FallbackLocale string
+	StrictLocales  bool
 
 	refs []string
+	page map[string]interface{}
+}
+
+// page is a Markdown page parsed for a layout.
+type page struct {
+	params  map[string]interface{}
+	content html.HTML
 }
 
 type executor interface {
@@ -30,54 +78,202 @@ type executor interface {
 	Refs() []string
 }
 
+// Run executes the pipe's template and writes the result to its output path. The output
+// is rendered to a temporary file in the same directory and renamed into place only
+// if execution succeeds, so a failed render leaves the last good output untouched. If
+// ErrorPage is set, html pipes in local mode replace the output with a page describing
+// the error instead.
+//
+// An In or Out of "-" reads the template from stdin or writes the result to stdout.
 func (p *Pipe) Run() error {
-	in, err := os.Open(p.In)
-	if err != nil {
-		return errors.Wrapf(err, "open input (path: %s)", p.In)
+	in := os.Stdin
+	if p.In != "-" {
+		fp, err := os.Open(p.In)
+		if err != nil {
+			return errors.Wrapf(err, "open input (path: %s)", p.In)
+		}
+		defer fp.Close()
+
+		in = fp
+	}
+
+	var pg *page
+	if p.Layout != "" {
+		src, err := io.ReadAll(in)
+		if err != nil {
+			return errors.Wrapf(err, "read page (path: %s)", p.In)
+		}
+
+		params, content, err := tmpl.ParsePage(src, p.Markdown)
+		if err != nil {
+			return errors.Wrapf(err, "parse page (path: %s)", p.In)
+		}
+
+		pg = &page{params: params, content: content}
+		p.page = params
+
+		fp, err := os.Open(p.Layout)
+		if err != nil {
+			return errors.Wrapf(err, "open layout (path: %s)", p.Layout)
+		}
+		defer fp.Close()
+
+		in = fp
+	}
+
+	if p.Out == "-" {
+		t, err := p.executor(in, os.Stdout, pg)
+		if err != nil {
+			return err
+		}
+
+		if err := t.Execute(os.Stdout, in); err != nil {
+			return errors.Wrapf(err, "execute (%T, in: %s)", t, in.Name())
+		}
+
+		p.refs = p.withLayout(t.Refs())
+
+		return nil
+	}
+
+	perm := os.FileMode(0o644)
+	if stat, err := os.Stat(p.Out); err == nil {
+		perm = stat.Mode().Perm()
 	}
-	defer in.Close()
 
-	out, err := os.OpenFile(p.Out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_SYNC, 0o644)
+	out, err := os.CreateTemp(filepath.Dir(p.Out), "."+filepath.Base(p.Out)+".*.tmp")
 	if err != nil {
-		return errors.Wrapf(err, "open output (path: %s)", p.Out)
+		return errors.Wrapf(err, "create temp output (path: %s)", p.Out)
 	}
+	defer os.Remove(out.Name())
 	defer out.Close()
 
-	var t executor
-	switch p.Format {
-	case "html":
-		t = tmpl.New().
-			WithMode(p.Mode).
-			WithBaseDir(p.BaseDir).
-			WithIO(in, out).
-			WithDelims(p.Delims[0], p.Delims[1]).
-			HTML().
-			WithMinify(p.Minify)
-	case "json":
-		t = tmpl.New().
-			WithMode(p.Mode).
-			WithBaseDir(p.BaseDir).
-			WithIO(in, out).
-			WithDelims(p.Delims[0], p.Delims[1]).
-			JSON().
-			WithMinify(p.Minify)
-	default:
-		t = tmpl.New().
-			WithMode(p.Mode).
-			WithBaseDir(p.BaseDir).
-			WithIO(in, out).
-			WithDelims(p.Delims[0], p.Delims[1])
+	t, err := p.executor(in, out, pg)
+	if err != nil {
+		return err
 	}
 
 	if err := t.Execute(out, in); err != nil {
-		return errors.Wrapf(err, "execute (%T, in: %s)", t, in.Name())
+		err = errors.Wrapf(err, "execute (%T, in: %s)", t, in.Name())
+
+		// Keep watching what the template read before it failed, as well as what it
+		// read the last time it succeeded.
+		p.refs = mergeRefs(p.refs, p.withLayout(t.Refs()))
+
+		if !p.ErrorPage || p.Mode != tmpl.ModeLocal || p.Format != "html" {
+			return err
+		}
+
+		if err := resetFile(out); err != nil {
+			return errors.Wrap(err, "reset temp output")
+		}
+
+		if err := writeErrorPage(out, p.In, err); err != nil {
+			return errors.Wrap(err, "write error page")
+		}
+
+		if err := commit(out, p.Out, perm); err != nil {
+			return errors.Wrapf(err, "commit output (path: %s)", p.Out)
+		}
+
+		return err
+	}
+
+	if err := commit(out, p.Out, perm); err != nil {
+		return errors.Wrapf(err, "commit output (path: %s)", p.Out)
 	}
 
-	p.refs = t.Refs()
+	p.refs = p.withLayout(t.Refs())
 
 	return nil
 }
 
+// Page returns the front matter of the Markdown page the pipe last rendered into its
+// layout, or nil if it doesn't have a layout.
+func (p *Pipe) Page() map[string]interface{} {
+	return p.page
+}
+
+// withLayout adds the pipe's layout, if it has one, to refs.
+func (p *Pipe) withLayout(refs []string) []string {
+	if p.Layout == "" {
+		return refs
+	}
+
+	return append(refs, p.Layout)
+}
+
+func (p *Pipe) executor(in io.Reader, out io.Writer, pg *page) (executor, error) {
+	t := tmpl.New().
+		WithMode(p.Mode).
+		WithBaseDir(p.BaseDir).
+		WithIO(in, out).
+		WithDelims(p.Delims[0], p.Delims[1]).
+		WithEnv(p.Env).
+		WithParams(p.Params).
+		WithCommands(p.Commands).
+		WithoutFuncs(p.DisableFuncs...).
+		WithLocale(p.Locale).
+		WithMarkdown(p.Markdown).
+		WithMinifyOptions(p.MinifyOptions).
+		WithAssets(p.Assets).
+		WithCSPHashes(p.CSPHashes, p.Out).
+		WithTextOptions(tmpl.TextOptions{
+			TrimTrailingWhitespace: p.TrimTrailingWhitespace,
+			CollapseBlankLines:     p.CollapseBlankLines,
+			LineEndings:            p.LineEndings,
+			TrailingNewline:        p.TrailingNewline,
+			Header:                 p.Header,
+		}, p.Out)
+
+	if pg != nil {
+		t = t.WithPage(pg.params, pg.content)
+	}
+
+	if p.Sandbox {
+		t = t.WithSandbox(p.SandboxAllow...)
+	}
+
+	if p.LocalesDir != "" {
+		tr, err := tmplfunc.LoadTranslations(t.FS(), tmplfunc.FSPath(t.FS(), "", p.LocalesDir))
+		if err != nil {
+			return nil, errors.Wrap(err, "load translations")
+		}
+
+		tr.Fallback = p.FallbackLocale
+		tr.Strict = p.StrictLocales
+		t = t.WithTranslations(tr)
+	}
+
+	switch p.Format {
+	case "html":
+		return t.HTML().WithMinify(p.Minify), nil
+	case "json":
+		return t.JSON().
+			WithMinify(p.Minify).
+			WithIndent(p.Indent).
+			WithSchema(p.Schema).
+			WithSortKeys(p.SortKeys).
+			WithTrailingNewline(p.TrailingNewline), nil
+	case "css":
+		return t.CSS().WithMinify(p.Minify), nil
+	case "js":
+		return t.JS().WithMinify(p.Minify), nil
+	case "svg":
+		return t.SVG().WithMinify(p.Minify), nil
+	case "xml":
+		return t.XML().WithMinify(p.Minify).WithIndent(p.Indent), nil
+	case "yaml":
+		return t.YAML().WithCanonical(p.Canonical).WithIndent(p.Indent), nil
+	case "toml":
+		return t.TOML().WithCanonical(p.Canonical).WithIndent(p.Indent), nil
+	case "csv":
+		return t.CSV().WithCanonical(p.Canonical), nil
+	default:
+		return t, nil
+	}
+}
+
 func (p *Pipe) AttachRefs(w *Watcher) {
 	for _, ref := range p.refs {
 		if err := w.AddRef(ref, p); err != nil {
@@ -85,3 +281,54 @@ func (p *Pipe) AttachRefs(w *Watcher) {
 		}
 	}
 }
+
+// mergeRefs returns the refs in a followed by those in b which aren't in a.
+func mergeRefs(a, b []string) []string {
+	seen := map[string]bool{}
+	for _, ref := range a {
+		seen[ref] = true
+	}
+
+	for _, ref := range b {
+		if !seen[ref] {
+			a = append(a, ref)
+			seen[ref] = true
+		}
+	}
+
+	return a
+}
+
+// resetFile discards anything written to fp so far.
+func resetFile(fp *os.File) error {
+	if err := fp.Truncate(0); err != nil {
+		return errors.Wrap(err, "truncate")
+	}
+
+	if _, err := fp.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "seek")
+	}
+
+	return nil
+}
+
+// commit flushes the temporary file fp to disk and renames it to path.
+func commit(fp *os.File, path string, perm os.FileMode) error {
+	if err := fp.Sync(); err != nil {
+		return errors.Wrap(err, "sync")
+	}
+
+	if err := fp.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+
+	if err := os.Chmod(fp.Name(), perm); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+
+	if err := os.Rename(fp.Name(), path); err != nil {
+		return errors.Wrap(err, "rename")
+	}
+
+	return nil
+}
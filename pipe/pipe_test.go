@@ -0,0 +1,141 @@
+package pipe
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmysawczuk/tmpl/tmpl"
+)
+
+// writeFile writes s to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name, s string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// tempFiles returns the temporary outputs left in dir.
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return matches
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	p := &Pipe{
+		In:  writeFile(t, dir, "in.tmpl", `{{ add 1 2 }}`),
+		Out: writeFile(t, dir, "out.txt", "old"),
+	}
+
+	if err := os.Chmod(p.Out, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if by, _ := os.ReadFile(p.Out); string(by) != "3" {
+		t.Errorf("output = %q, want %q", by, "3")
+	}
+
+	if stat, err := os.Stat(p.Out); err != nil || stat.Mode().Perm() != 0o600 {
+		t.Errorf("output mode = %v, %v, want %v", stat.Mode().Perm(), err, os.FileMode(0o600))
+	}
+
+	if tmp := tempFiles(t, dir); len(tmp) > 0 {
+		t.Errorf("temporary files left behind: %q", tmp)
+	}
+}
+
+func TestRunKeepsOutputOnError(t *testing.T) {
+	dir := t.TempDir()
+	ref := writeFile(t, dir, "partial.txt", "partial")
+
+	p := &Pipe{
+		In:     writeFile(t, dir, "in.tmpl", `{{ ref "partial.txt" }}{{ index (list 1) 5 }}`),
+		Out:    writeFile(t, dir, "out.html", "last good"),
+		Format: "html",
+		Mode:   tmpl.ModeProduction,
+
+		BaseDir:   dir,
+		ErrorPage: true,
+	}
+
+	if err := p.Run(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if by, _ := os.ReadFile(p.Out); string(by) != "last good" {
+		t.Errorf("output = %q, want it unchanged", by)
+	}
+
+	if tmp := tempFiles(t, dir); len(tmp) > 0 {
+		t.Errorf("temporary files left behind: %q", tmp)
+	}
+
+	if !reflect.DeepEqual(p.refs, []string{ref}) {
+		t.Errorf("refs = %q, want the files read before the error, %q", p.refs, []string{ref})
+	}
+}
+
+func TestRunErrorPage(t *testing.T) {
+	dir := t.TempDir()
+
+	p := &Pipe{
+		In:        writeFile(t, dir, "in.tmpl", `<p>{{ index (list "<b>") 5 }}</p>`),
+		Out:       filepath.Join(dir, "out.html"),
+		Format:    "html",
+		Mode:      tmpl.ModeLocal,
+		ErrorPage: true,
+	}
+
+	err := p.Run()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	by, rerr := os.ReadFile(p.Out)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+
+	page := string(by)
+	for _, want := range []string{"tmpl couldn't render this page", "<code>" + p.In + "</code>", "index out of range", "<script"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("error page doesn't contain %q:\n%s", want, page)
+		}
+	}
+
+	// Only html pipes get an error page.
+	p.Format, p.Out = "", writeFile(t, dir, "out.txt", "last good")
+	if err := p.Run(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if by, _ := os.ReadFile(p.Out); string(by) != "last good" {
+		t.Errorf("output = %q, want it unchanged", by)
+	}
+}
+
+func TestMergeRefs(t *testing.T) {
+	got := mergeRefs([]string{"a", "b"}, []string{"b", "c", "c", "a", "d"})
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeRefs = %q, want %q", got, want)
+	}
+}
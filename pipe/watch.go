@@ -1,8 +1,11 @@
 package pipe
 
 import (
+	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
@@ -13,8 +16,16 @@ type Watcher struct {
 
 	w *fsnotify.Watcher
 
-	pipes map[string]*Pipe
-	refs  map[string]*Pipe
+	pipes map[string][]*Pipe
+	refs  map[string][]*Pipe
+	globs []glob
+}
+
+// glob is a glob input added with AddGlob.
+type glob struct {
+	root  string
+	match func(string) bool
+	new   func(string) ([]*Pipe, error)
 }
 
 func New(active bool) (*Watcher, error) {
@@ -27,8 +38,8 @@ func New(active bool) (*Watcher, error) {
 		return &Watcher{
 			active: true,
 			w:      watcher,
-			pipes:  map[string]*Pipe{},
-			refs:   map[string]*Pipe{},
+			pipes:  map[string][]*Pipe{},
+			refs:   map[string][]*Pipe{},
 		}, nil
 	}
 
@@ -55,11 +66,9 @@ func (w *Watcher) AddPipe(p *Pipe) error {
 
 	// TODO: check for existence here too?
 
-	w.w.Add(path)
+	w.watch(path)
 
-	if _, ok := w.pipes[path]; !ok {
-		w.pipes[p.In] = p
-	}
+	w.pipes[path] = appendPipe(w.pipes[path], p)
 
 	return nil
 }
@@ -74,11 +83,215 @@ func (w *Watcher) AddRef(ref string, pipe *Pipe) error {
 		return errors.Errorf("filepath: abs (path: %s)", ref)
 	}
 
-	w.w.Add(path)
-	w.refs[path] = pipe
+	w.watch(path)
+	w.refs[path] = appendPipe(w.refs[path], pipe)
+	return nil
+}
+
+// AddGlob watches root and every directory beneath it for files which match a glob
+// input. newPipes returns the pipes for a file created after the watcher started; the
+// pipes of a matching file which is removed are dropped.
+func (w *Watcher) AddGlob(root string, match func(string) bool, newPipes func(string) ([]*Pipe, error)) error {
+	if !w.active {
+		return nil
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return errors.Errorf("filepath: abs (path: %s)", root)
+	}
+
+	if err := w.watchTree(root); err != nil {
+		return err
+	}
+
+	w.globs = append(w.globs, glob{root: root, match: match, new: newPipes})
 	return nil
 }
 
+// watchTree watches dir and the directories beneath it, skipping hidden ones.
+func (w *Watcher) watchTree(dir string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		if err := w.w.Add(p); err != nil {
+			log.Printf("watcher: watch %s: %s", p, err)
+		}
+
+		return nil
+	})
+
+	return errors.Wrapf(err, "walk (path: %s)", dir)
+}
+
+// globsFor returns the globs whose root contains path.
+func (w *Watcher) globsFor(path string) []glob {
+	var tbr []glob
+	for _, g := range w.globs {
+		if path == g.root || strings.HasPrefix(path, g.root+string(filepath.Separator)) {
+			tbr = append(tbr, g)
+		}
+	}
+
+	return tbr
+}
+
+// created adds pipes for path, if it's a new file matching a glob input. If path is a
+// new directory, it's watched, and pipes are added for the matching files already in it,
+// which can be written before the watch starts; their paths are returned so they can be
+// run.
+func (w *Watcher) created(path string) []string {
+	globs := w.globsFor(path)
+	if len(globs) == 0 {
+		return nil
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	if !fi.IsDir() {
+		w.addGlobbed(globs, path)
+		return nil
+	}
+
+	if err := w.watchTree(path); err != nil {
+		log.Printf("watcher: %s", err)
+	}
+
+	var tbr []string
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && w.addGlobbed(globs, p) {
+			tbr = append(tbr, p)
+		}
+
+		return nil
+	})
+
+	return tbr
+}
+
+// addGlobbed adds pipes for path from the globs it matches, unless it already has pipes.
+func (w *Watcher) addGlobbed(globs []glob, path string) bool {
+	if len(w.pipes[path]) > 0 {
+		return false
+	}
+
+	added := false
+	for _, g := range globs {
+		if !g.match(path) {
+			continue
+		}
+
+		pipes, err := g.new(path)
+		if err != nil {
+			log.Printf("%s", errors.Wrapf(err, "pipeline (path: %s)", path))
+			continue
+		}
+
+		for _, p := range pipes {
+			if err := w.AddPipe(p); err != nil {
+				log.Printf("couldn't watch path %s: %s", p.In, err)
+				continue
+			}
+
+			added = true
+		}
+	}
+
+	if added {
+		log.Println("added:", path)
+	}
+
+	return added
+}
+
+// removed drops the pipes for path, if it was matched by a glob input and no longer
+// exists. Its outputs are left in place.
+func (w *Watcher) removed(path string) {
+	if len(w.pipes[path]) == 0 || len(w.globsFor(path)) == 0 {
+		return
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+
+	gone := w.pipes[path]
+	delete(w.pipes, path)
+
+	for ref, pipes := range w.refs {
+		kept := pipes[:0]
+		for _, p := range pipes {
+			if !containsPipe(gone, p) {
+				kept = append(kept, p)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(w.refs, ref)
+		} else {
+			w.refs[ref] = kept
+		}
+	}
+
+	log.Println("removed:", path)
+}
+
+func containsPipe(pipes []*Pipe, p *Pipe) bool {
+	for _, v := range pipes {
+		if v == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watch watches the directory containing path, rather than path itself. Outputs are
+// written to a temporary file and renamed into place, as are files saved by many
+// editors, which replaces the file a watch on path would follow; the directory's watch
+// reports the new file as created.
+func (w *Watcher) watch(path string) {
+	if err := w.w.Add(filepath.Dir(path)); err != nil {
+		log.Printf("watcher: watch %s: %s", filepath.Dir(path), err)
+	}
+}
+
+// appendPipe adds p to pipes if it isn't already there. Several pipes can share an
+// input or a ref, like a block rendered once per locale.
+func appendPipe(pipes []*Pipe, p *Pipe) []*Pipe {
+	if containsPipe(pipes, p) {
+		return pipes
+	}
+
+	return append(pipes, p)
+}
+
+// run runs pipes and re-attaches their refs, logging the outcome.
+func (w *Watcher) run(pipes []*Pipe) {
+	for _, pipe := range pipes {
+		if err := pipe.Run(); err != nil {
+			log.Printf("%s", errors.Wrapf(err, "pipeline (path: %s)", pipe.In))
+		} else {
+			log.Println(" --> wrote:", pipe.Out)
+		}
+
+		pipe.AttachRefs(w)
+	}
+}
+
 func (w *Watcher) Watch(notify chan string) {
 	if !w.active {
 		return
@@ -91,32 +304,26 @@ func (w *Watcher) Watch(notify chan string) {
 				return
 			}
 
-			if event.Op&fsnotify.Write == fsnotify.Write {
-				log.Println("changed:", event.Name, event.Op)
-
-				for path, pipe := range w.pipes {
-					if path == event.Name {
-						if err := pipe.Run(); err != nil {
-							log.Printf("%s", errors.Wrapf(err, "pipeline (path: %s)", pipe.In))
-						}
-						log.Println(" --> wrote:", pipe.Out)
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				w.removed(event.Name)
+				continue
+			}
 
-						pipe.AttachRefs(w)
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
 
-						continue
-					}
+			if event.Op&fsnotify.Create != 0 {
+				for _, path := range w.created(event.Name) {
+					w.run(w.pipes[path])
 				}
+			}
 
-				for ref, pipe := range w.refs {
-					if ref == event.Name {
-						if err := pipe.Run(); err != nil {
-							log.Printf("%s", errors.Wrapf(err, "pipeline (path: %s)", pipe.In))
-						}
-						log.Println(" --> wrote:", pipe.Out)
+			if len(w.pipes[event.Name]) > 0 || len(w.refs[event.Name]) > 0 {
+				log.Println("changed:", event.Name, event.Op)
 
-						pipe.AttachRefs(w)
-					}
-				}
+				w.run(w.pipes[event.Name])
+				w.run(w.refs[event.Name])
 
 				for {
 					select {
@@ -0,0 +1,100 @@
+package pipe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWatcherGlob(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	match := func(p string) bool { return strings.HasSuffix(p, ".tmpl") }
+	newPipes := func(in string) ([]*Pipe, error) {
+		return []*Pipe{{In: in, Out: strings.TrimSuffix(in, ".tmpl") + ".txt"}}, nil
+	}
+
+	if err := w.AddGlob(dir, match, newPipes); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, s string) string {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	page := write("page.tmpl", "page")
+	if got := w.created(page); len(got) != 0 {
+		t.Errorf("created(file) = %q, want none", got)
+	}
+
+	if len(w.pipes[page]) != 1 {
+		t.Fatalf("created(%s): got %d pipes, want 1", page, len(w.pipes[page]))
+	}
+
+	// A second event for the same file doesn't add another pipe.
+	w.created(page)
+	if len(w.pipes[page]) != 1 {
+		t.Errorf("created(%s) again: got %d pipes, want 1", page, len(w.pipes[page]))
+	}
+
+	notes := write("notes.md", "notes")
+	w.created(notes)
+	if len(w.pipes[notes]) != 0 {
+		t.Errorf("created(%s): got %d pipes, want 0", notes, len(w.pipes[notes]))
+	}
+
+	// Files written into a new directory before it's watched are picked up with it.
+	nested := write("blog/2020/post.tmpl", "post")
+	got := w.created(filepath.Join(dir, "blog"))
+	if len(got) != 1 || got[0] != nested {
+		t.Errorf("created(dir) = %q, want [%s]", got, nested)
+	}
+
+	w.run(w.pipes[nested])
+	if by, err := os.ReadFile(filepath.Join(dir, "blog", "2020", "post.txt")); err != nil || string(by) != "post" {
+		t.Errorf("run(%s) wrote %q, %v; want %q", nested, by, err, "post")
+	}
+
+	ref := write("partial.html", "")
+	if err := w.AddRef(ref, w.pipes[page][0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file which still exists, like one an editor saved by renaming, keeps its pipes.
+	w.removed(page)
+	if len(w.pipes[page]) != 1 {
+		t.Errorf("removed(%s) while it exists: got %d pipes, want 1", page, len(w.pipes[page]))
+	}
+
+	if err := os.Remove(page); err != nil {
+		t.Fatal(err)
+	}
+
+	w.removed(page)
+	if len(w.pipes[page]) != 0 {
+		t.Errorf("removed(%s): got %d pipes, want 0", page, len(w.pipes[page]))
+	}
+
+	if len(w.refs[ref]) != 0 {
+		t.Errorf("removed(%s): ref %s still has %d pipes", page, ref, len(w.refs[ref]))
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jimmysawczuk/tmpl/pipe"
+	"github.com/jimmysawczuk/tmpl/tmpl"
+	"github.com/pkg/errors"
+)
+
+// kvFlag collects repeated key=value flags into a map.
+type kvFlag map[string]string
+
+func (f kvFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f kvFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return errors.Errorf("expected key=value, got %q", s)
+	}
+
+	f[k] = v
+	return nil
+}
+
+// runRender renders a single template without a config file:
+//
+//	tmpl render [file|-] [-o out|-] [flags]
+//
+// The input and output default to stdin and stdout.
+func runRender(args []string) error {
+	var (
+		out     string
+		format  string
+		minify  bool
+		sandbox bool
+		delims  string
+		params  = kvFlag{}
+		env     = kvFlag{}
+	)
+
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  tmpl render [file|-] [-o out|-] [options]\n\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&out, "o", "-", "path to write output to, or - for stdout")
+	fs.StringVar(&format, "format", "", `output format ("html", "json", "css", "js", "svg", "xml", "yaml", "toml", "csv", or "")`)
+	fs.BoolVar(&minify, "minify", false, "minify the output")
+	fs.StringVar(&delims, "delims", "", `template delimiters, separated by a comma (e.g. "[[,]]")`)
+	fs.BoolVar(&sandbox, "sandbox", false, "restrict file access to the working directory")
+	fs.Var(params, "param", "set a param (key=value, repeatable); values are parsed as JSON if possible")
+	fs.Var(env, "env", "set an environment variable (KEY=VALUE, repeatable)")
+
+	// Allow the input path to appear before, after or between flags.
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return errors.Wrap(err, "parse flags")
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		positional, args = append(positional, args[0]), args[1:]
+	}
+
+	in := "-"
+	switch len(positional) {
+	case 0:
+	case 1:
+		in = positional[0]
+	default:
+		fs.Usage()
+		return errors.Errorf("expected at most one input, got %d", len(positional))
+	}
+
+	p := &pipe.Pipe{
+		In:      in,
+		Out:     out,
+		BaseDir: ".",
+		Format:  format,
+		Mode:    tmpl.ModeProduction,
+		Minify:  minify,
+		Env:     env,
+		Params:  map[string]interface{}{},
+		Sandbox: sandbox,
+	}
+
+	if delims != "" {
+		l, r, ok := strings.Cut(delims, ",")
+		if !ok || l == "" || r == "" {
+			return errors.Errorf("invalid delims (expected left,right, got %q)", delims)
+		}
+
+		p.Delims = [2]string{l, r}
+	}
+
+	for k, v := range params {
+		var val interface{}
+		if err := json.Unmarshal([]byte(v), &val); err != nil {
+			val = v
+		}
+
+		p.Params[k] = val
+	}
+
+	if err := p.Run(); err != nil {
+		return errors.Wrapf(err, "render (path: %s)", in)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunRender(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"page.tmpl":  `{{ .Params.name }} {{ .Params.n | add 1 }} {{ env "GREETING" }}`,
+		"delim.tmpl": `[[ .Params.name ]] {{ x }}`,
+	})
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{
+			"params and env",
+			[]string{"-param", "name=tmpl", "-param", "n=41", "-env", "GREETING=hi", filepath.Join(dir, "page.tmpl"), "-o"},
+			"",
+			"tmpl 42 hi",
+		},
+		{
+			"input between flags",
+			[]string{"-param", "name=x", filepath.Join(dir, "delim.tmpl"), "-delims", "[[,]]", "-o"},
+			"",
+			"x {{ x }}",
+		},
+		{
+			"stdin with json params",
+			[]string{"-param", `name=["a"]`, "-param", "n=1", "-o"},
+			"page.tmpl",
+			"[a] 2 ",
+		},
+	}
+
+	for _, test := range tests {
+		out := filepath.Join(dir, "out.txt")
+		args := append(test.args, out)
+
+		if test.stdin != "" {
+			stdin, err := os.Open(filepath.Join(dir, test.stdin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+
+			orig := os.Stdin
+			os.Stdin = stdin
+			defer func() { os.Stdin = orig }()
+		}
+
+		if err := runRender(args); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if by, _ := os.ReadFile(out); string(by) != test.want {
+			t.Errorf("%s = %q, want %q", test.name, by, test.want)
+		}
+	}
+}
+
+func TestRunRenderErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"bad.tmpl": `{{ index (list) 1 }}`, "ok.tmpl": "ok"})
+
+	out := filepath.Join(dir, "out.txt")
+	tests := map[string][]string{
+		"execute":      {filepath.Join(dir, "bad.tmpl"), "-o", out},
+		"two inputs":   {filepath.Join(dir, "ok.tmpl"), filepath.Join(dir, "ok.tmpl"), "-o", out},
+		"delims":       {filepath.Join(dir, "ok.tmpl"), "-delims", "[[", "-o", out},
+		"missing file": {filepath.Join(dir, "missing.tmpl"), "-o", out},
+	}
+
+	for name, args := range tests {
+		if err := runRender(args); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestKVFlag(t *testing.T) {
+	f := kvFlag{}
+	for _, s := range []string{"a=1", "b=x=y", "c="} {
+		if err := f.Set(s); err != nil {
+			t.Errorf("Set(%q): %s", s, err)
+		}
+	}
+
+	if f["a"] != "1" || f["b"] != "x=y" || f["c"] != "" || len(f) != 3 {
+		t.Errorf("kvFlag = %v", f)
+	}
+
+	for _, s := range []string{"a", "=1"} {
+		if err := f.Set(s); err == nil {
+			t.Errorf("Set(%q): expected an error", s)
+		}
+	}
+}
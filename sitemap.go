@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmysawczuk/tmpl/config"
+	"github.com/jimmysawczuk/tmpl/pipe"
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+	"github.com/pkg/errors"
+)
+
+// changeFreqs are the values the sitemap protocol allows for changefreq.
+var changeFreqs = map[string]bool{
+	"always":  true,
+	"hourly":  true,
+	"daily":   true,
+	"weekly":  true,
+	"monthly": true,
+	"yearly":  true,
+	"never":   true,
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// validateSitemap returns an error if the sitemap's defaults aren't allowed by the
+// sitemap protocol.
+func validateSitemap(s config.Sitemap) error {
+	if s.ChangeFreq != "" && !changeFreqs[s.ChangeFreq] {
+		return errors.Errorf("unknown changefreq %q", s.ChangeFreq)
+	}
+
+	if s.Priority != nil && (*s.Priority < 0 || *s.Priority > 1) {
+		return errors.Errorf("priority must be between 0 and 1, got %v", *s.Priority)
+	}
+
+	return nil
+}
+
+// writeSitemap writes a sitemap listing the pages written by the html pipes into
+// publicDir.
+func writeSitemap(s config.Sitemap, baseURL string, publicDir string, pipes []*pipe.Pipe) error {
+	set := sitemapURLSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  []sitemapURL{},
+	}
+
+	seen := map[string]bool{}
+	for _, p := range pipes {
+		if p.Format != "html" {
+			continue
+		}
+
+		u, ok := pageURL(publicDir, p.Out)
+		if !ok || seen[u] || excluded(s.Exclude, u) {
+			continue
+		}
+
+		page := p.Page()
+		if v, ok := page["sitemap"].(bool); ok && !v {
+			continue
+		}
+
+		seen[u] = true
+
+		entry := sitemapURL{
+			Loc:        absoluteURL(baseURL, u),
+			ChangeFreq: s.ChangeFreq,
+		}
+
+		if p.In != "-" {
+			lastmod, err := lastModified(p.In, page)
+			if err != nil {
+				return errors.Wrap(err, "lastmod")
+			}
+
+			entry.LastMod = lastmod.UTC().Format(time.RFC3339)
+		}
+
+		if v, ok := page["changefreq"].(string); ok {
+			if !changeFreqs[v] {
+				return errors.Errorf("unknown changefreq %q (path: %s)", v, p.In)
+			}
+
+			entry.ChangeFreq = v
+		}
+
+		priority := s.Priority
+		if v, ok := page["priority"]; ok {
+			f, ok := v.(float64)
+			if i, isInt := v.(int); isInt {
+				f, ok = float64(i), true
+			}
+
+			if !ok || f < 0 || f > 1 {
+				return errors.Errorf("priority must be a number between 0 and 1 (path: %s)", p.In)
+			}
+
+			priority = &f
+		}
+
+		if priority != nil {
+			entry.Priority = strconv.FormatFloat(*priority, 'f', -1, 64)
+		}
+
+		set.URLs = append(set.URLs, entry)
+	}
+
+	sort.Slice(set.URLs, func(i, j int) bool {
+		return set.URLs[i].Loc < set.URLs[j].Loc
+	})
+
+	out := s.Out
+	if out == "" {
+		out = "sitemap.xml"
+	}
+
+	return writeXML(filepath.Join(publicDir, out), set)
+}
+
+// lastModified returns the time the page at in was last changed: its front matter's
+// lastmod, updated or date, or else the file's modification time.
+func lastModified(in string, page map[string]interface{}) (time.Time, error) {
+	for _, key := range []string{"lastmod", "updated", "date"} {
+		if v, ok := page[key]; ok {
+			t, err := tmplfunc.ToTime(v)
+			if err != nil {
+				return time.Time{}, errors.Wrapf(err, "front matter: %s (path: %s)", key, in)
+			}
+
+			return t, nil
+		}
+	}
+
+	stat, err := os.Stat(in)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "stat (path: %s)", in)
+	}
+
+	return stat.ModTime(), nil
+}
+
+// pageURL returns the URL path for the file at out, if it's inside publicDir. Index
+// pages are served at their directory's URL.
+func pageURL(publicDir string, out string) (string, bool) {
+	rel, err := filepath.Rel(publicDir, out)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	u := "/" + filepath.ToSlash(rel)
+	if path.Base(u) == "index.html" {
+		u = strings.TrimSuffix(u, "index.html")
+	}
+
+	return u, true
+}
+
+// absoluteURL resolves u, a URL path, against baseURL. URLs which are already absolute
+// are returned as-is.
+func absoluteURL(baseURL string, u string) string {
+	if strings.Contains(u, "://") {
+		return u
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(u, "/")
+}
+
+// excluded reports whether the URL path u matches any of the patterns.
+func excluded(patterns []string, u string) bool {
+	name := strings.Split(strings.Trim(u, "/"), "/")
+	for _, pattern := range patterns {
+		if matchElems(strings.Split(strings.Trim(pattern, "/"), "/"), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeXML writes v to the provided path as an indented XML document.
+func writeXML(p string, v interface{}) error {
+	by, err := xml.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "xml: marshal")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return errors.Wrapf(err, "mkdir (path: %s)", filepath.Dir(p))
+	}
+
+	by = append([]byte(xml.Header), append(by, '\n')...)
+	if err := os.WriteFile(p, by, 0o644); err != nil {
+		return errors.Wrapf(err, "write file (path: %s)", p)
+	}
+
+	return nil
+}
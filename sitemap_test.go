@@ -0,0 +1,181 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jimmysawczuk/tmpl/config"
+	"github.com/jimmysawczuk/tmpl/pipe"
+)
+
+// writeFiles writes files, keyed by their path relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, s := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// pagePipes returns a pipe rendering each page in dir's content directory into its
+// layout, writing to its public directory, and runs them.
+func pagePipes(t *testing.T, dir string, pages ...string) []*pipe.Pipe {
+	t.Helper()
+
+	pipes := []*pipe.Pipe{}
+	for _, name := range pages {
+		p := &pipe.Pipe{
+			In:     filepath.Join(dir, "content", name+".md"),
+			Out:    filepath.Join(dir, "public", filepath.FromSlash(name)+".html"),
+			Layout: filepath.Join(dir, "layout.html"),
+			Format: "html",
+		}
+
+		if err := os.MkdirAll(filepath.Dir(p.Out), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.Run(); err != nil {
+			t.Fatalf("run %s: %s", name, err)
+		}
+
+		pipes = append(pipes, p)
+	}
+
+	return pipes
+}
+
+func TestWriteSitemap(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"layout.html":           "{{ .Content }}",
+		"content/index.md":      "---\ndate: 2020-01-02\npriority: 0\n---\nHome",
+		"content/about.md":      "---\nchangefreq: daily\npriority: 0.8\n---\nAbout",
+		"content/blog/index.md": "---\nlastmod: 2021-03-04T05:06:07Z\ndate: 2020-01-01\n---\nBlog",
+		"content/hidden.md":     "---\nsitemap: false\n---\nHidden",
+		"content/secret/a.md":   "Secret",
+	})
+
+	mtime := time.Date(2022, 5, 6, 7, 8, 9, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(dir, "content", "about.md"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	pipes := pagePipes(t, dir, "index", "about", "blog/index", "hidden", "secret/a")
+
+	half := 0.5
+	s := config.Sitemap{ChangeFreq: "weekly", Priority: &half, Exclude: []string{"/secret/**"}}
+	if err := writeSitemap(s, "https://example.com/", filepath.Join(dir, "public"), pipes); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "public", "sitemap.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url>
+		<loc>https://example.com/</loc>
+		<lastmod>2020-01-02T00:00:00Z</lastmod>
+		<changefreq>weekly</changefreq>
+		<priority>0</priority>
+	</url>
+	<url>
+		<loc>https://example.com/about.html</loc>
+		<lastmod>2022-05-06T07:08:09Z</lastmod>
+		<changefreq>daily</changefreq>
+		<priority>0.8</priority>
+	</url>
+	<url>
+		<loc>https://example.com/blog/</loc>
+		<lastmod>2021-03-04T05:06:07Z</lastmod>
+		<changefreq>weekly</changefreq>
+		<priority>0.5</priority>
+	</url>
+</urlset>
+`
+	if string(got) != want {
+		t.Errorf("sitemap = %s, want %s", got, want)
+	}
+}
+
+func TestWriteSitemapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+	}{
+		{"date", "---\ndate: yesterday\n---\nPage"},
+		{"lastmod", "---\nlastmod: [2020]\n---\nPage"},
+		{"changefreq", "---\nchangefreq: sometimes\n---\nPage"},
+		{"priority", "---\npriority: 2\n---\nPage"},
+		{"priority type", "---\npriority: high\n---\nPage"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{
+			"layout.html":      "{{ .Content }}",
+			"content/index.md": test.page,
+		})
+
+		pipes := pagePipes(t, dir, "index")
+		if err := writeSitemap(config.Sitemap{}, "https://example.com", filepath.Join(dir, "public"), pipes); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestValidateSitemap(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		s   config.Sitemap
+		err bool
+	}{
+		{config.Sitemap{}, false},
+		{config.Sitemap{ChangeFreq: "hourly", Priority: f(0)}, false},
+		{config.Sitemap{Priority: f(1)}, false},
+		{config.Sitemap{Priority: f(-0.1)}, true},
+		{config.Sitemap{Priority: f(1.5)}, true},
+		{config.Sitemap{ChangeFreq: "fortnightly"}, true},
+	}
+
+	for _, test := range tests {
+		if err := validateSitemap(test.s); (err != nil) != test.err {
+			t.Errorf("validateSitemap(%+v) = %v, want error: %v", test.s, err, test.err)
+		}
+	}
+}
+
+func TestPageURL(t *testing.T) {
+	public := filepath.Join("site", "public")
+
+	tests := []struct {
+		out  string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(public, "index.html"), "/", true},
+		{filepath.Join(public, "blog", "index.html"), "/blog/", true},
+		{filepath.Join(public, "blog", "post.html"), "/blog/post.html", true},
+		{filepath.Join("site", "other.html"), "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := pageURL(public, test.out)
+		if got != test.want || ok != test.ok {
+			t.Errorf("pageURL(%q) = %q, %v, want %q, %v", test.out, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package tmpl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+	text "text/template"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Data formats for DataTmpl.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatCSV  = "csv"
+)
+
+// DataTmpl is a text template whose output is a YAML, TOML or CSV document. The output
+// is checked to parse.
+type DataTmpl struct {
+	*Tmpl
+
+	// Format is FormatYAML, FormatTOML or FormatCSV.
+	Format string
+
+	// Canonical, if set, re-serializes the output: YAML and TOML with sorted keys and
+	// consistent indentation, and CSV with consistent quoting.
+	Canonical bool
+
+	// Indent is the indentation used for canonical YAML (spaces only; the default is two)
+	// and TOML (nested tables aren't indented by default).
+	Indent string
+}
+
+func (t *DataTmpl) WithCanonical(c bool) *DataTmpl {
+	t.Canonical = c
+	return t
+}
+
+func (t *DataTmpl) WithIndent(indent string) *DataTmpl {
+	t.Indent = indent
+	return t
+}
+
+func (t *DataTmpl) Execute(out io.Writer, in io.Reader) error {
+	buf := bytes.Buffer{}
+	if _, err := io.Copy(&buf, in); err != nil {
+		return errors.Wrap(err, "io: copy (input)")
+	}
+
+	tmpl, err := text.New("output").Funcs(t.funcs()).Delims(t.leftDelim, t.rightDelim).Parse(buf.String())
+	if err != nil {
+		return errors.Wrap(err, "compile template")
+	}
+
+	buf.Reset()
+
+	if err := tmpl.Execute(&buf, t); err != nil {
+		return errors.Wrap(err, "execute template")
+	}
+
+	var by []byte
+	switch t.Format {
+	case FormatYAML:
+		by, err = canonicalYAML(buf.Bytes(), t.Indent)
+	case FormatTOML:
+		by, err = canonicalTOML(buf.Bytes(), t.Indent)
+	case FormatCSV:
+		by, err = canonicalCSV(buf.Bytes())
+	default:
+		return errors.Errorf("unknown data format %q", t.Format)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%s: validate", t.Format)
+	}
+
+	if t.Canonical {
+		buf = *bytes.NewBuffer(by)
+	}
+
+	if _, err := io.Copy(out, &buf); err != nil {
+		return errors.Wrap(err, "io: copy (output)")
+	}
+
+	return nil
+}
+
+// canonicalYAML parses each document in the YAML stream doc, and returns them
+// re-serialized with sorted keys and block style, indented by indent. It returns an
+// error if a document doesn't parse or has a duplicate key.
+func canonicalYAML(doc []byte, indent string) ([]byte, error) {
+	spaces := 2
+	if indent != "" {
+		if strings.Trim(indent, " ") != "" {
+			return nil, errors.New("yaml can only be indented with spaces")
+		}
+
+		spaces = len(indent)
+	}
+
+	dst := bytes.Buffer{}
+	enc := yaml.NewEncoder(&dst)
+	enc.SetIndent(spaces)
+
+	d := yaml.NewDecoder(bytes.NewReader(doc))
+	for i := 1; ; i++ {
+		var node yaml.Node
+		err := d.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "document %d", i)
+		}
+
+		if err := checkYAMLKeys(&node); err != nil {
+			return nil, errors.Wrapf(err, "document %d", i)
+		}
+
+		// Sorting could move an alias before its anchor, so documents with aliases keep
+		// their key order.
+		if !hasYAMLAlias(&node) {
+			sortYAML(&node)
+		}
+
+		if err := enc.Encode(&node); err != nil {
+			return nil, errors.Wrapf(err, "document %d: encode", i)
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, errors.Wrap(err, "encode")
+	}
+
+	return dst.Bytes(), nil
+}
+
+// checkYAMLKeys returns an error if a mapping in n has the same key twice.
+func checkYAMLKeys(n *yaml.Node) error {
+	if n.Kind == yaml.MappingNode {
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+				continue
+			}
+
+			if seen[key.Value] {
+				return errors.Errorf("line %d: duplicate key %q", key.Line, key.Value)
+			}
+			seen[key.Value] = true
+		}
+	}
+
+	for _, c := range n.Content {
+		if err := checkYAMLKeys(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasYAMLAlias(n *yaml.Node) bool {
+	if n.Kind == yaml.AliasNode {
+		return true
+	}
+
+	for _, c := range n.Content {
+		if hasYAMLAlias(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortYAML sorts the keys of every mapping in n and switches flow style collections to
+// block style.
+func sortYAML(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode || n.Kind == yaml.SequenceNode {
+		n.Style &^= yaml.FlowStyle
+	}
+
+	if n.Kind == yaml.MappingNode {
+		pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+		}
+
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return pairs[i][0].Value < pairs[j][0].Value
+		})
+
+		for i, pair := range pairs {
+			n.Content[2*i], n.Content[2*i+1] = pair[0], pair[1]
+		}
+	}
+
+	for _, c := range n.Content {
+		sortYAML(c)
+	}
+}
+
+// canonicalTOML parses the TOML document doc, and returns it re-serialized with sorted
+// keys. If indent is set, nested tables are indented by it.
+func canonicalTOML(doc []byte, indent string) ([]byte, error) {
+	v := map[string]interface{}{}
+	if err := toml.Unmarshal(doc, &v); err != nil {
+		var derr *toml.DecodeError
+		if errors.As(err, &derr) {
+			row, col := derr.Position()
+			return nil, errors.Errorf("line %d, column %d: %s", row, col, derr.Error())
+		}
+
+		return nil, err
+	}
+
+	dst := bytes.Buffer{}
+	enc := toml.NewEncoder(&dst)
+	if indent != "" {
+		enc.SetIndentTables(true)
+		enc.SetIndentSymbol(indent)
+	}
+
+	if err := enc.Encode(v); err != nil {
+		return nil, errors.Wrap(err, "encode")
+	}
+
+	return dst.Bytes(), nil
+}
+
+// canonicalCSV parses the CSV document doc, and returns it re-serialized, quoting only
+// the fields which need it. It returns an error if a record doesn't parse or has a
+// different number of fields than the first.
+func canonicalCSV(doc []byte) ([]byte, error) {
+	records, err := csv.NewReader(bytes.NewReader(doc)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	dst := bytes.Buffer{}
+	if err := csv.NewWriter(&dst).WriteAll(records); err != nil {
+		return nil, errors.Wrap(err, "encode")
+	}
+
+	return dst.Bytes(), nil
+}
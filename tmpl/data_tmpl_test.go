@@ -0,0 +1,73 @@
+package tmpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDataTmpl(t *testing.T) {
+	tests := []struct {
+		name      string
+		tmpl      func() *DataTmpl
+		canonical bool
+		indent    string
+		in        string
+		want      string
+	}{
+		{"yaml unchanged", New().YAML, false, "", "b: {{ add 1 1 }}\na: [1, 2]\n", "b: 2\na: [1, 2]\n"},
+		{"yaml canonical", New().YAML, true, "", "b: {{ add 1 1 }}\na: {y: 1, x: [1, 2]}\n", "a:\n  x:\n    - 1\n    - 2\n  y: 1\nb: 2\n"},
+		{"yaml canonical indent", New().YAML, true, "    ", "a: {b: 1}\n", "a:\n    b: 1\n"},
+		{"yaml documents", New().YAML, true, "", "b: 1\na: 2\n---\nc: 3\n", "a: 2\nb: 1\n---\nc: 3\n"},
+		{"yaml aliases keep order", New().YAML, true, "", "z: &x 1\na: *x\n", "z: &x 1\na: *x\n"},
+		{"toml unchanged", New().TOML, false, "", "b = {{ add 1 1 }}\na = 'x'\n", "b = 2\na = 'x'\n"},
+		{"toml canonical", New().TOML, true, "", "b = 2\na = 'x'\n[t]\nk = true\n", "a = 'x'\nb = 2\n\n[t]\nk = true\n"},
+		{"toml canonical indent", New().TOML, true, "  ", "[t]\nk = 1\n", "[t]\n  k = 1\n"},
+		{"csv unchanged", New().CSV, false, "", "\"a\",\"b\"\n{{ add 0 1 }},2\n", "\"a\",\"b\"\n1,2\n"},
+		{"csv canonical", New().CSV, true, "", "\"a\",\"b c\"\n\"1\",\"x,y\"\n", "a,b c\n1,\"x,y\"\n"},
+		{"csv from toCSV", New().CSV, false, "", `{{ toCSV (list (dict "b" 1 "a" "x")) }}`, "a,b\nx,1"},
+	}
+
+	for _, test := range tests {
+		buf := bytes.Buffer{}
+		err := test.tmpl().WithCanonical(test.canonical).WithIndent(test.indent).Execute(&buf, strings.NewReader(test.in))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDataTmplInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		tmpl   func() *DataTmpl
+		indent string
+		in     string
+	}{
+		{"yaml syntax", New().YAML, "", "a: [1, 2\n"},
+		{"yaml duplicate key", New().YAML, "", "a: 1\nb:\n  c: 1\n  c: 2\n"},
+		{"yaml tab indent", New().YAML, "\t", "a: 1\n"},
+		{"toml syntax", New().TOML, "", "a = \n"},
+		{"toml duplicate key", New().TOML, "", "a = 1\na = 2\n"},
+		{"csv field count", New().CSV, "", "a,b\n1\n"},
+		{"csv quote", New().CSV, "", "a,\"b\n"},
+		{"template", New().YAML, "", "{{ index (list) 1 }}"},
+	}
+
+	for _, test := range tests {
+		if err := test.tmpl().WithIndent(test.indent).Execute(&bytes.Buffer{}, strings.NewReader(test.in)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	d := New().YAML()
+	d.Format = "ini"
+	if err := d.Execute(&bytes.Buffer{}, strings.NewReader("a = 1")); err == nil {
+		t.Errorf("unknown format: expected an error")
+	}
+}
@@ -0,0 +1,26 @@
+package tmpl
+
+import (
+	"io/fs"
+	"os"
+)
+
+// osFS is an fs.FS backed by the operating system's filesystem. Unlike os.DirFS, it
+// accepts any path os.Open does, so paths are resolved relative to the working directory
+// and may be absolute.
+type osFS struct{}
+
+// OSPaths marks osFS as a tmplfunc.OSFS.
+func (osFS) OSPaths() {}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
@@ -2,31 +2,324 @@ package tmpl
 
 import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 
+// builtins are the functions available in every template.
+var builtins = []tmplfunc.Func{
+	{
+		Name: "asset",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Asset(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "asset returns the URL for the file at the provided path in the public directory, fingerprinted in production mode. It creates a ref.",
+			Example: `{{ asset "/css/style.css" }}`,
+		},
+	},
+	{
+		Name: "autoreload",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Autoreload(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "autoreload returns an HTML snippet that you can embed in your templates to automatically reload the page when a change is detected.",
+			Example: `{{ autoreload }}`,
+		},
+	},
+	{
+		Name: "base64",
+		Fn:   tmplfunc.Base64,
+		Meta: tmplfunc.Meta{
+			Doc:     "base64 encodes the provided bytes using standard base64 encoding.",
+			Example: `{{ qrcode "https://example.com" | base64 }}`,
+		},
+	},
+	{
+		Name: "base64url",
+		Fn:   tmplfunc.Base64URL,
+		Meta: tmplfunc.Meta{
+			Doc:     "base64url encodes the provided bytes using URL-safe base64 encoding.",
+			Example: `{{ qrcode "https://example.com" | base64url }}`,
+		},
+	},
+	{
+		Name: "bundle",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.BundleFunc(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "bundle concatenates a configured bundle, or a type (\"css\" or \"js\") followed by files in the public directory, writes it and returns its URL. It's minified and fingerprinted in production, and creates a ref to each file.",
+			Example: `{{ bundle "css" "/css/reset.css" "/css/site.css" }}`,
+		},
+	},
+	{
+		Name: "cspHash",
+		Fn:   tmplfunc.CSPHash,
+		Meta: tmplfunc.Meta{
+			Doc:     "cspHash returns the Content-Security-Policy source, like 'sha256-...', for the provided inline script or style.",
+			Example: `{{ cspHash "console.log('hi')" }}`,
+		},
+	},
+	{
+		Name: "cspHashes",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.CSPHashList(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "cspHashes returns the CSP hashes of the inline scripts (\"script\") or styles (\"style\") in every html block rendered before this one.",
+			Example: `script-src 'self' {{ join " " (cspHashes "script") }}`,
+		},
+	},
+	{
+		Name: "env",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.EnvFunc(c.EnvVars()) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "env returns the environment variable defined at the provided key. Variables set in the config file take precedence.",
+			Example: `{{ env "NODE_ENV" }}`,
+		},
+	},
+	{
+		Name: "file",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.File(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "file loads the file at the path provided, relative to the base directory, and returns its contents. It does not create a ref.",
+			Example: `{{ file "some-letter.txt" }}`,
+		},
+	},
+	{
+		Name: "formatTime",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.LocalFormatTime(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "formatTime formats the provided time with the provided Go layout. Month and weekday names are in the template's locale.",
+			Example: `{{ now | formatTime "Jan 2, 2006 3:04 PM" }}`,
+		},
+	},
+	{
+		Name: "getJSON",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.GetJSON(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "getJSON loads the file at the provided path, relative to the base directory, and unmarshals it. It does not create a ref.",
+			Example: `{{ (getJSON "REVISION.json").sha }}`,
+		},
+	},
+	{
+		Name: "highlight",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Highlight(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "highlight returns the provided code, in the provided language, as syntax-highlighted HTML using the block's highlight options.",
+			Example: `{{ highlight "go" (file "main.go") }}`,
+		},
+	},
+	{
+		Name: "highlightCSS",
+		Fn:   tmplfunc.HighlightCSS,
+		Meta: tmplfunc.Meta{
+			Doc:     "highlightCSS returns the stylesheet for code highlighted with CSS classes in the provided style.",
+			Example: `<style>{{ highlightCSS "monokai" }}</style>`,
+		},
+	},
+	{
+		Name: "inline",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Inline(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "inline loads the file at the path provided, relative to the base directory, and returns its contents. It creates a ref.",
+			Example: `{{ inline "some-letter.txt" }}`,
+		},
+	},
+	{
+		Name: "inlineBundle",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.InlineBundle(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "inlineBundle concatenates a bundle like bundle does, but returns its contents instead of writing it.",
+			Example: `<style>{{ inlineBundle "critical" }}</style>`,
+		},
+	},
+	{
+		Name: "integrity",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Integrity(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "integrity returns the Subresource Integrity value, like sha384-..., for the file at the provided path in the public directory. It creates a ref.",
+			Example: `<script src="{{ asset "/js/app.js" }}" integrity="{{ integrity "/js/app.js" }}"></script>`,
+		},
+	},
+	{
+		Name: "jsonify",
+		Fn:   tmplfunc.JSONify,
+		Meta: tmplfunc.Meta{
+			Doc:     "jsonify marshals the provided input as a JSON string.",
+			Example: `{{ now | jsonify }}`,
+		},
+	},
+	{
+		Name: "markdown",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Markdown(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "markdown reads the Markdown file at the provided path, relative to the base directory, and converts it to HTML using the block's markdown options. It creates a ref.",
+			Example: `{{ markdown "posts/hello.md" }}`,
+		},
+	},
+	{
+		Name: "markdownTOC",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.MarkdownTOC(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "markdownTOC returns a table of contents for the provided Markdown as nested lists linking to each heading.",
+			Example: `{{ markdownTOC (file "post.md") }}`,
+		},
+	},
+	{
+		Name: "markdownify",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Markdownify(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "markdownify converts the provided Markdown string to HTML using the block's markdown options.",
+			Example: `{{ .Params.summary | markdownify }}`,
+		},
+	},
+	{
+		Name: "now",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.NowFunc(c.Now()) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "now returns the time of the template's execution in the local timezone.",
+			Example: `{{ now | jsonify }}`,
+		},
+	},
+	{
+		Name: "parseTime",
+		Fn:   tmplfunc.ParseTime,
+		Meta: tmplfunc.Meta{
+			Doc:     "parseTime returns a time.Time from the provided string in RFC3339 format.",
+			Example: `{{ parseTime "2021-11-28T10:09:00Z" }}`,
+		},
+	},
+	{
+		Name: "qrcode",
+		Fn:   tmplfunc.QRCode,
+		Meta: tmplfunc.Meta{
+			Doc:     "qrcode encodes the provided data into a PNG-formatted image, optionally with a width and recovery level.",
+			Example: `{{ qrcode "https://example.com" 256 | base64 }}`,
+		},
+	},
+	{
+		Name: "ref",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Ref(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "ref creates a ref to the provided path so that an automatic update is triggered when the file at that path is changed. ref produces no output.",
+			Example: `{{ ref "public/style.css" }}`,
+		},
+	},
+	{
+		Name: "safeCSS",
+		Fn:   tmplfunc.SafeCSS,
+		Meta: tmplfunc.Meta{
+			Doc:     "safeCSS marks the provided string as safe CSS so it's not further escaped.",
+			Example: `{{ "a { color: red }" | safeCSS }}`,
+		},
+	},
+	{
+		Name: "safeHTML",
+		Fn:   tmplfunc.SafeHTML,
+		Meta: tmplfunc.Meta{
+			Doc:     "safeHTML marks the provided string as safe HTML so it's not further escaped.",
+			Example: `{{ "<b>bold</b>" | safeHTML }}`,
+		},
+	},
+	{
+		Name: "safeHTMLAttr",
+		Fn:   tmplfunc.SafeAttr,
+		Meta: tmplfunc.Meta{
+			Doc:     "safeHTMLAttr marks the provided string as a safe HTML attribute so it's not further escaped.",
+			Example: `<a {{ "href=\"/\"" | safeHTMLAttr }}>`,
+		},
+	},
+	{
+		Name: "safeJS",
+		Fn:   tmplfunc.SafeJS,
+		Meta: tmplfunc.Meta{
+			Doc:     "safeJS marks the provided string as safe JavaScript so it's not further escaped.",
+			Example: `{{ "alert(1)" | safeJS }}`,
+		},
+	},
+	{
+		Name: "seq",
+		Fn:   tmplfunc.Seq,
+		Meta: tmplfunc.Meta{
+			Doc:     "seq returns a slice of n elements. Useful for range.",
+			Example: `{{ range seq 3 }}Hello!{{ end }}`,
+		},
+	},
+	{
+		Name: "svg",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.SVG(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "svg reads the file at the provided path, relative to the base directory, and returns its contents as safe HTML. It creates a ref.",
+			Example: `{{ svg "path-to-svg.svg" }}`,
+		},
+	},
+	{
+		Name: "t",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Translate(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "t returns the message at the provided key in the template's locale, filling in {placeholders} from a map, a count, or key/value pairs. It creates a ref to the catalog.",
+			Example: `{{ t "inbox.unread" 3 }}`,
+		},
+	},
+	{
+		Name: "timeIn",
+		Fn:   tmplfunc.TimeIn,
+		Meta: tmplfunc.Meta{
+			Doc:     "timeIn returns the provided time in the provided timezone.",
+			Example: `{{ now | timeIn "America/Los_Angeles" }}`,
+		},
+	},
+	{
+		Name: "toJSONSorted",
+		Fn:   tmplfunc.ToJSONSorted,
+		Meta: tmplfunc.Meta{
+			Doc:     "toJSONSorted marshals the provided input as a JSON string with every object's keys sorted, including struct fields.",
+			Example: `{{ .Page | toJSONSorted }}`,
+		},
+	},
+	{
+		Name: "toPrettyJSON",
+		Fn:   tmplfunc.ToPrettyJSON,
+		Meta: tmplfunc.Meta{
+			Doc:     "toPrettyJSON marshals the provided input as a JSON string indented by two spaces.",
+			Example: `<pre>{{ .Params | toPrettyJSON }}</pre>`,
+		},
+	},
+}
+
+func init() {
+	for _, funcs := range [][]tmplfunc.Func{
+		builtins,
+		stringFuncs,
+		listFuncs,
+		dictFuncs,
+		defaultFuncs,
+		mathFuncs,
+		numberFuncs,
+		collectionFuncs,
+		regexFuncs,
+		textFuncs,
+		dateFuncs,
+		xmlFuncs,
+		dataFuncs,
+	} {
+		for _, f := range funcs {
+			tmplfunc.Register(f.Name, f.Fn, f.Meta)
+		}
+	}
+}
+
+// funcs returns the template's function map: every registered function, then the
+// template's commands and its own functions, less any disabled functions.
 func (t *Tmpl) funcs() map[string]interface{} {
-	return map[string]interface{}{
-		"add":          tmplfunc.Add,
-		"asset":        tmplfunc.Asset,
-		"autoreload":   tmplfunc.Autoreload(t),
-		"base64":       tmplfunc.Base64,
-		"base64url":    tmplfunc.Base64URL,
-		"env":          tmplfunc.EnvFunc(t.envVars),
-		"file":         tmplfunc.File,
-		"formatTime":   tmplfunc.FormatTime,
-		"getJSON":      tmplfunc.GetJSON,
-		"inline":       tmplfunc.Inline(t),
-		"jsonify":      tmplfunc.JSONify,
-		"markdown":     tmplfunc.Markdown,
-		"now":          tmplfunc.NowFunc(t.now),
-		"parseTime":    tmplfunc.ParseTime,
-		"qrcode":       tmplfunc.QRCode,
-		"ref":          tmplfunc.Ref(t),
-		"safeCSS":      tmplfunc.SafeCSS,
-		"safeHTML":     tmplfunc.SafeHTML,
-		"safeHTMLAttr": tmplfunc.SafeAttr,
-		"safeJS":       tmplfunc.SafeJS,
-		"seq":          tmplfunc.Seq,
-		"sub":          tmplfunc.Sub,
-		"svg":          tmplfunc.SVG(t),
-		"timeIn":       tmplfunc.TimeIn,
+	m := map[string]interface{}{}
+
+	for _, f := range tmplfunc.Registered() {
+		m[f.Name] = f.Resolve(t)
 	}
+
+	for name, c := range t.commands {
+		m[name] = tmplfunc.Exec(t, c)
+	}
+
+	for name, fn := range t.extraFuncs {
+		m[name] = fn
+	}
+
+	for _, name := range t.disabledFuncs {
+		delete(m, name)
+	}
+
+	return m
 }
@@ -0,0 +1,71 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// collectionFuncs query lists of records, like those returned by getJSON.
+var collectionFuncs = []tmplfunc.Func{
+	{
+		Name: "where",
+		Fn:   tmplfunc.Where,
+		Meta: tmplfunc.Meta{
+			Doc:     "where returns the elements of a list whose field matches a condition. The operator is one of ==, !=, <, <=, >, >=, in, \"not in\" or contains, and defaults to ==.",
+			Example: `{{ where .Params.posts "author.name" "==" "Jimmy" }}`,
+		},
+	},
+	{
+		Name: "sortBy",
+		Fn:   tmplfunc.SortBy,
+		Meta: tmplfunc.Meta{
+			Doc:     "sortBy returns a copy of a list sorted by a field, in \"asc\" (the default) or \"desc\" order. Elements without the field come first.",
+			Example: `{{ sortBy .Params.posts "date" "desc" }}`,
+		},
+	},
+	{
+		Name: "groupBy",
+		Fn:   tmplfunc.GroupBy,
+		Meta: tmplfunc.Meta{
+			Doc:     "groupBy groups the elements of a list by a field, returning a map from each value to its elements.",
+			Example: `{{ range $tag, $posts := groupBy .Params.posts "tag" }}{{ $tag }}: {{ len $posts }}{{ end }}`,
+		},
+	},
+	{
+		Name: "countBy",
+		Fn:   tmplfunc.CountBy,
+		Meta: tmplfunc.Meta{
+			Doc:     "countBy counts the elements of a list by a field, returning a map from each value to its count.",
+			Example: `{{ range $tag, $n := countBy .Params.posts "tag" }}{{ $tag }} ({{ $n }}){{ end }}`,
+		},
+	},
+	{
+		Name: "limit",
+		Fn:   tmplfunc.Limit,
+		Meta: tmplfunc.Meta{
+			Doc:     "limit returns at most the first n elements of a list.",
+			Example: `{{ limit .Params.posts 5 }}`,
+		},
+	},
+	{
+		Name: "offset",
+		Fn:   tmplfunc.Offset,
+		Meta: tmplfunc.Meta{
+			Doc:     "offset returns the elements of a list after the first n.",
+			Example: `{{ offset .Params.posts 5 }}`,
+		},
+	},
+	{
+		Name: "pluck",
+		Fn:   tmplfunc.Pluck,
+		Meta: tmplfunc.Meta{
+			Doc:     "pluck returns the value of a field in each element of a list.",
+			Example: `{{ pluck .Params.posts "title" | join ", " }}`,
+		},
+	},
+	{
+		Name: "sum",
+		Fn:   tmplfunc.Sum,
+		Meta: tmplfunc.Meta{
+			Doc:     "sum returns the sum of a list of numbers, or of a field in each element of a list.",
+			Example: `{{ sum .Params.orders "total" }}`,
+		},
+	},
+}
@@ -0,0 +1,31 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// dataFuncs serialize values in formats other than JSON.
+var dataFuncs = []tmplfunc.Func{
+	{
+		Name: "toYAML",
+		Fn:   tmplfunc.ToYAML,
+		Meta: tmplfunc.Meta{
+			Doc:     "toYAML marshals the provided input as YAML, indented by two spaces.",
+			Example: `{{ .Params.resources | toYAML | nindent 8 }}`,
+		},
+	},
+	{
+		Name: "toTOML",
+		Fn:   tmplfunc.ToTOML,
+		Meta: tmplfunc.Meta{
+			Doc:     "toTOML marshals the provided map as a TOML document.",
+			Example: `{{ dict "name" "app" "port" 8080 | toTOML }}`,
+		},
+	},
+	{
+		Name: "toCSV",
+		Fn:   tmplfunc.ToCSV,
+		Meta: tmplfunc.Meta{
+			Doc:     "toCSV writes the provided rows, lists or maps, as CSV. Rows of maps get a header row of their sorted keys.",
+			Example: `{{ getJSON "data/users.json" | toCSV }}`,
+		},
+	},
+}
@@ -0,0 +1,119 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// dateFuncs parse, format and manipulate times.
+var dateFuncs = []tmplfunc.Func{
+	{
+		Name: "parseTimeLayout",
+		Fn:   tmplfunc.ParseTimeLayout,
+		Meta: tmplfunc.Meta{
+			Doc:     "parseTimeLayout parses a string using the provided Go layout.",
+			Example: `{{ parseTimeLayout "02/01/2006" "28/11/2021" }}`,
+		},
+	},
+	{
+		Name: "parseDate",
+		Fn:   tmplfunc.ParseDate,
+		Meta: tmplfunc.Meta{
+			Doc:     "parseDate parses a string, detecting its format from common layouts like RFC 3339, RFC 1123, \"2006-01-02\" and \"Jan 2, 2006\".",
+			Example: `{{ parseDate "Nov 28, 2021" }}`,
+		},
+	},
+	{
+		Name: "strftime",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Strftime(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "strftime formats a time using C strftime-style directives, with names in the template's locale.",
+			Example: `{{ now | strftime "%A, %B %e %Y" }}`,
+		},
+	},
+	{
+		Name: "dateAdd",
+		Fn:   tmplfunc.DateAdd,
+		Meta: tmplfunc.Meta{
+			Doc:     "dateAdd adds an offset like \"1y2mo\", \"-3d\" or \"1h30m\" to a time. Years, months, weeks and days are added on the calendar.",
+			Example: `{{ now | dateAdd "1w" }}`,
+		},
+	},
+	{
+		Name: "dateDiff",
+		Fn:   tmplfunc.DateDiff,
+		Meta: tmplfunc.Meta{
+			Doc:     "dateDiff returns the number of whole years, months, weeks, days, hours, minutes or seconds between two times.",
+			Example: `{{ dateDiff "days" .Params.start now }}`,
+		},
+	},
+	{
+		Name: "humanizeDuration",
+		Fn:   tmplfunc.HumanizeDuration,
+		Meta: tmplfunc.Meta{
+			Doc:     "humanizeDuration describes a duration (or a number of seconds) in words using its two largest units.",
+			Example: `{{ "90m" | humanizeDuration }}`,
+		},
+	},
+	{
+		Name: "timeAgo",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.TimeAgo(c.Now()) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "timeAgo describes a time relative to now, like \"3 days ago\" or \"in 2 hours\".",
+			Example: `{{ .Params.published | timeAgo }}`,
+		},
+	},
+	{
+		Name: "startOf",
+		Fn:   tmplfunc.StartOf,
+		Meta: tmplfunc.Meta{
+			Doc:     "startOf returns the beginning of the minute, hour, day, week, month, quarter or year containing a time. Weeks start on Monday.",
+			Example: `{{ now | startOf "week" }}`,
+		},
+	},
+	{
+		Name: "endOf",
+		Fn:   tmplfunc.EndOf,
+		Meta: tmplfunc.Meta{
+			Doc:     "endOf returns the last instant of the minute, hour, day, week, month, quarter or year containing a time.",
+			Example: `{{ now | endOf "month" }}`,
+		},
+	},
+	{
+		Name: "isoWeek",
+		Fn:   tmplfunc.ISOWeek,
+		Meta: tmplfunc.Meta{
+			Doc:     "isoWeek returns the ISO 8601 week number of a time.",
+			Example: `{{ now | isoWeek }}`,
+		},
+	},
+	{
+		Name: "unix",
+		Fn:   tmplfunc.Unix,
+		Meta: tmplfunc.Meta{
+			Doc:     "unix returns a time as a Unix timestamp, in seconds.",
+			Example: `{{ now | unix }}`,
+		},
+	},
+	{
+		Name: "fromUnix",
+		Fn:   tmplfunc.FromUnix,
+		Meta: tmplfunc.Meta{
+			Doc:     "fromUnix returns the time corresponding to a Unix timestamp, in seconds.",
+			Example: `{{ fromUnix 1638094140 }}`,
+		},
+	},
+	{
+		Name: "monthName",
+		Fn:   tmplfunc.MonthName,
+		Meta: tmplfunc.Meta{
+			Doc:     "monthName returns the name of a time's month in the provided locale.",
+			Example: `{{ now | monthName "fr" }}`,
+		},
+	},
+	{
+		Name: "dayName",
+		Fn:   tmplfunc.DayName,
+		Meta: tmplfunc.Meta{
+			Doc:     "dayName returns the name of a time's weekday in the provided locale.",
+			Example: `{{ now | dayName "de" }}`,
+		},
+	},
+}
@@ -0,0 +1,239 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// listFuncs are Sprig-compatible list functions.
+var listFuncs = []tmplfunc.Func{
+	{
+		Name: "list",
+		Fn:   tmplfunc.List,
+		Meta: tmplfunc.Meta{
+			Doc:     "list returns its arguments as a list.",
+			Example: `{{ list 1 2 3 }}`,
+		},
+	},
+	{
+		Name: "first",
+		Fn:   tmplfunc.First,
+		Meta: tmplfunc.Meta{
+			Doc:     "first returns the first element of a list.",
+			Example: `{{ list 1 2 3 | first }}`,
+		},
+	},
+	{
+		Name: "last",
+		Fn:   tmplfunc.Last,
+		Meta: tmplfunc.Meta{
+			Doc:     "last returns the last element of a list.",
+			Example: `{{ list 1 2 3 | last }}`,
+		},
+	},
+	{
+		Name: "rest",
+		Fn:   tmplfunc.Rest,
+		Meta: tmplfunc.Meta{
+			Doc:     "rest returns every element of a list except the first.",
+			Example: `{{ list 1 2 3 | rest }}`,
+		},
+	},
+	{
+		Name: "initial",
+		Fn:   tmplfunc.Initial,
+		Meta: tmplfunc.Meta{
+			Doc:     "initial returns every element of a list except the last.",
+			Example: `{{ list 1 2 3 | initial }}`,
+		},
+	},
+	{
+		Name: "append",
+		Fn:   tmplfunc.Append,
+		Meta: tmplfunc.Meta{
+			Doc:     "append returns a new list with an element added to the end.",
+			Example: `{{ append (list 1 2) 3 }}`,
+		},
+	},
+	{
+		Name: "push",
+		Fn:   tmplfunc.Append,
+		Meta: tmplfunc.Meta{
+			Doc:     "push is an alias for append.",
+			Example: `{{ push (list 1 2) 3 }}`,
+		},
+	},
+	{
+		Name: "prepend",
+		Fn:   tmplfunc.Prepend,
+		Meta: tmplfunc.Meta{
+			Doc:     "prepend returns a new list with an element added to the beginning.",
+			Example: `{{ prepend (list 2 3) 1 }}`,
+		},
+	},
+	{
+		Name: "concat",
+		Fn:   tmplfunc.Concat,
+		Meta: tmplfunc.Meta{
+			Doc:     "concat returns a new list with the elements of each of the provided lists.",
+			Example: `{{ concat (list 1 2) (list 3 4) }}`,
+		},
+	},
+	{
+		Name: "uniq",
+		Fn:   tmplfunc.Uniq,
+		Meta: tmplfunc.Meta{
+			Doc:     "uniq returns a new list with duplicate elements removed.",
+			Example: `{{ list 1 1 2 | uniq }}`,
+		},
+	},
+	{
+		Name: "reverse",
+		Fn:   tmplfunc.Reverse,
+		Meta: tmplfunc.Meta{
+			Doc:     "reverse returns a new list with the elements in reverse order.",
+			Example: `{{ list 1 2 3 | reverse }}`,
+		},
+	},
+	{
+		Name: "has",
+		Fn:   tmplfunc.Has,
+		Meta: tmplfunc.Meta{
+			Doc:     "has reports whether a list contains an element.",
+			Example: `{{ if has 2 (list 1 2 3) }}yes{{ end }}`,
+		},
+	},
+	{
+		Name: "without",
+		Fn:   tmplfunc.Without,
+		Meta: tmplfunc.Meta{
+			Doc:     "without returns a new list without any of the provided elements.",
+			Example: `{{ without (list 1 2 3) 2 }}`,
+		},
+	},
+	{
+		Name: "compact",
+		Fn:   tmplfunc.Compact,
+		Meta: tmplfunc.Meta{
+			Doc:     "compact returns a new list without any empty elements.",
+			Example: `{{ list 1 "" 2 | compact }}`,
+		},
+	},
+}
+
+// dictFuncs are Sprig-compatible map functions.
+var dictFuncs = []tmplfunc.Func{
+	{
+		Name: "dict",
+		Fn:   tmplfunc.Dict,
+		Meta: tmplfunc.Meta{
+			Doc:     "dict returns a map built from the provided key/value pairs.",
+			Example: `{{ $d := dict "name" "tmpl" "lang" "go" }}`,
+		},
+	},
+	{
+		Name: "get",
+		Fn:   tmplfunc.Get,
+		Meta: tmplfunc.Meta{
+			Doc:     "get returns the value at a key in a map, or an empty string if it's not set.",
+			Example: `{{ get $d "name" }}`,
+		},
+	},
+	{
+		Name: "set",
+		Fn:   tmplfunc.Set,
+		Meta: tmplfunc.Meta{
+			Doc:     "set sets a key in a map and returns the map.",
+			Example: `{{ $_ := set $d "name" "tmpl" }}`,
+		},
+	},
+	{
+		Name: "unset",
+		Fn:   tmplfunc.Unset,
+		Meta: tmplfunc.Meta{
+			Doc:     "unset removes a key from a map and returns the map.",
+			Example: `{{ $_ := unset $d "name" }}`,
+		},
+	},
+	{
+		Name: "hasKey",
+		Fn:   tmplfunc.HasKey,
+		Meta: tmplfunc.Meta{
+			Doc:     "hasKey reports whether a key is set in a map.",
+			Example: `{{ if hasKey $d "name" }}yes{{ end }}`,
+		},
+	},
+	{
+		Name: "keys",
+		Fn:   tmplfunc.Keys,
+		Meta: tmplfunc.Meta{
+			Doc:     "keys returns the sorted keys of the provided maps.",
+			Example: `{{ keys $d | join ", " }}`,
+		},
+	},
+	{
+		Name: "values",
+		Fn:   tmplfunc.Values,
+		Meta: tmplfunc.Meta{
+			Doc:     "values returns the values of a map, ordered by key.",
+			Example: `{{ values $d }}`,
+		},
+	},
+	{
+		Name: "merge",
+		Fn:   tmplfunc.Merge,
+		Meta: tmplfunc.Meta{
+			Doc:     "merge deeply merges maps into the first map, which takes precedence, and returns it.",
+			Example: `{{ merge $page $defaults }}`,
+		},
+	},
+	{
+		Name: "pick",
+		Fn:   tmplfunc.Pick,
+		Meta: tmplfunc.Meta{
+			Doc:     "pick returns a new map with only the provided keys.",
+			Example: `{{ pick $d "name" "lang" }}`,
+		},
+	},
+	{
+		Name: "omit",
+		Fn:   tmplfunc.Omit,
+		Meta: tmplfunc.Meta{
+			Doc:     "omit returns a new map without the provided keys.",
+			Example: `{{ omit $d "password" }}`,
+		},
+	},
+}
+
+// defaultFuncs are Sprig-compatible functions for defaults and conditionals.
+var defaultFuncs = []tmplfunc.Func{
+	{
+		Name: "default",
+		Fn:   tmplfunc.Default,
+		Meta: tmplfunc.Meta{
+			Doc:     "default returns the given value, or the default if the given value is empty.",
+			Example: `{{ .Params.title | default "Untitled" }}`,
+		},
+	},
+	{
+		Name: "empty",
+		Fn:   tmplfunc.Empty,
+		Meta: tmplfunc.Meta{
+			Doc:     "empty reports whether a value is empty.",
+			Example: `{{ if empty .Params.title }}untitled{{ end }}`,
+		},
+	},
+	{
+		Name: "coalesce",
+		Fn:   tmplfunc.Coalesce,
+		Meta: tmplfunc.Meta{
+			Doc:     "coalesce returns the first non-empty argument.",
+			Example: `{{ coalesce .Params.title .Params.name "Untitled" }}`,
+		},
+	},
+	{
+		Name: "ternary",
+		Fn:   tmplfunc.Ternary,
+		Meta: tmplfunc.Meta{
+			Doc:     "ternary returns the first argument if the condition is true, and the second otherwise.",
+			Example: `{{ ternary "yes" "no" .Params.enabled }}`,
+		},
+	},
+}
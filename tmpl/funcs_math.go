@@ -0,0 +1,123 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// mathFuncs are arithmetic functions which accept ints, floats and numeric strings.
+var mathFuncs = []tmplfunc.Func{
+	{
+		Name: "add",
+		Fn:   tmplfunc.Add,
+		Meta: tmplfunc.Meta{
+			Doc:     "add returns the sum of its arguments.",
+			Example: `{{ add 2 2.5 "3" }}`,
+		},
+	},
+	{
+		Name: "sub",
+		Fn:   tmplfunc.Sub,
+		Meta: tmplfunc.Meta{
+			Doc:     "sub returns the first argument minus each of the rest.",
+			Example: `{{ sub 3 2 }}`,
+		},
+	},
+	{
+		Name: "mul",
+		Fn:   tmplfunc.Mul,
+		Meta: tmplfunc.Meta{
+			Doc:     "mul returns the product of its arguments.",
+			Example: `{{ mul .price 1.2 }}`,
+		},
+	},
+	{
+		Name: "div",
+		Fn:   tmplfunc.Div,
+		Meta: tmplfunc.Meta{
+			Doc:     "div returns the first argument divided by each of the rest. Integers are divided using integer division.",
+			Example: `{{ div 10 4.0 }}`,
+		},
+	},
+	{
+		Name: "mod",
+		Fn:   tmplfunc.Mod,
+		Meta: tmplfunc.Meta{
+			Doc:     "mod returns the remainder of the first argument divided by the second.",
+			Example: `{{ mod 10 3 }}`,
+		},
+	},
+	{
+		Name: "min",
+		Fn:   tmplfunc.Min,
+		Meta: tmplfunc.Meta{
+			Doc:     "min returns the smallest of its arguments.",
+			Example: `{{ min 3 1 2 }}`,
+		},
+	},
+	{
+		Name: "max",
+		Fn:   tmplfunc.Max,
+		Meta: tmplfunc.Meta{
+			Doc:     "max returns the largest of its arguments.",
+			Example: `{{ max 3 1 2 }}`,
+		},
+	},
+	{
+		Name: "round",
+		Fn:   tmplfunc.Round,
+		Meta: tmplfunc.Meta{
+			Doc:     "round rounds a number to the nearest integer, or to the provided number of decimal places.",
+			Example: `{{ round 3.14159 2 }}`,
+		},
+	},
+	{
+		Name: "floor",
+		Fn:   tmplfunc.Floor,
+		Meta: tmplfunc.Meta{
+			Doc:     "floor rounds a number down to the nearest integer.",
+			Example: `{{ floor 3.7 }}`,
+		},
+	},
+	{
+		Name: "ceil",
+		Fn:   tmplfunc.Ceil,
+		Meta: tmplfunc.Meta{
+			Doc:     "ceil rounds a number up to the nearest integer.",
+			Example: `{{ ceil 3.2 }}`,
+		},
+	},
+}
+
+// numberFuncs format numbers.
+var numberFuncs = []tmplfunc.Func{
+	{
+		Name: "formatNumber",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.FormatNumber(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "formatNumber formats a number with the provided number of decimal places and thousands separators, in the template's locale.",
+			Example: `{{ 1234567.891 | formatNumber 2 }}`,
+		},
+	},
+	{
+		Name: "formatCurrency",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.FormatCurrency(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "formatCurrency formats a number as an amount of a currency, given as an ISO 4217 code or a symbol, in the template's locale.",
+			Example: `{{ 1234.5 | formatCurrency "USD" }}`,
+		},
+	},
+	{
+		Name: "percent",
+		Fn:   tmplfunc.Factory(func(c tmplfunc.Context) interface{} { return tmplfunc.Percent(c) }),
+		Meta: tmplfunc.Meta{
+			Doc:     "percent formats a ratio as a percentage with the provided number of decimal places, in the template's locale.",
+			Example: `{{ 0.256 | percent 1 }}`,
+		},
+	},
+	{
+		Name: "humanizeBytes",
+		Fn:   tmplfunc.HumanizeBytes,
+		Meta: tmplfunc.Meta{
+			Doc:     "humanizeBytes formats a number of bytes using the largest unit (KB, MB, ...) that fits.",
+			Example: `{{ 1536 | humanizeBytes }}`,
+		},
+	},
+}
@@ -0,0 +1,167 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// stringFuncs are Sprig-compatible string functions.
+var stringFuncs = []tmplfunc.Func{
+	{
+		Name: "upper",
+		Fn:   tmplfunc.Upper,
+		Meta: tmplfunc.Meta{
+			Doc:     "upper returns the provided string in upper case.",
+			Example: `{{ "hello" | upper }}`,
+		},
+	},
+	{
+		Name: "lower",
+		Fn:   tmplfunc.Lower,
+		Meta: tmplfunc.Meta{
+			Doc:     "lower returns the provided string in lower case.",
+			Example: `{{ "HELLO" | lower }}`,
+		},
+	},
+	{
+		Name: "title",
+		Fn:   tmplfunc.Title,
+		Meta: tmplfunc.Meta{
+			Doc:     "title returns the provided string with the first letter of each word in upper case.",
+			Example: `{{ "hello world" | title }}`,
+		},
+	},
+	{
+		Name: "trim",
+		Fn:   tmplfunc.Trim,
+		Meta: tmplfunc.Meta{
+			Doc:     "trim removes leading and trailing whitespace.",
+			Example: `{{ "  hello  " | trim }}`,
+		},
+	},
+	{
+		Name: "trimPrefix",
+		Fn:   tmplfunc.TrimPrefix,
+		Meta: tmplfunc.Meta{
+			Doc:     "trimPrefix removes the provided prefix.",
+			Example: `{{ "v1.2.3" | trimPrefix "v" }}`,
+		},
+	},
+	{
+		Name: "trimSuffix",
+		Fn:   tmplfunc.TrimSuffix,
+		Meta: tmplfunc.Meta{
+			Doc:     "trimSuffix removes the provided suffix.",
+			Example: `{{ "index.html" | trimSuffix ".html" }}`,
+		},
+	},
+	{
+		Name: "replace",
+		Fn:   tmplfunc.Replace,
+		Meta: tmplfunc.Meta{
+			Doc:     "replace replaces every instance of the first argument with the second.",
+			Example: `{{ "a-b-c" | replace "-" "_" }}`,
+		},
+	},
+	{
+		Name: "repeat",
+		Fn:   tmplfunc.Repeat,
+		Meta: tmplfunc.Meta{
+			Doc:     "repeat repeats the provided string n times.",
+			Example: `{{ "ab" | repeat 3 }}`,
+		},
+	},
+	{
+		Name: "split",
+		Fn:   tmplfunc.Split,
+		Meta: tmplfunc.Meta{
+			Doc:     "split splits the provided string around a separator, returning a map with keys _0, _1 and so on.",
+			Example: `{{ (split "." "a.b.c")._1 }}`,
+		},
+	},
+	{
+		Name: "splitList",
+		Fn:   tmplfunc.SplitList,
+		Meta: tmplfunc.Meta{
+			Doc:     "splitList splits the provided string around a separator, returning a list.",
+			Example: `{{ splitList "," "a,b,c" }}`,
+		},
+	},
+	{
+		Name: "join",
+		Fn:   tmplfunc.Join,
+		Meta: tmplfunc.Meta{
+			Doc:     "join joins the elements of a list with a separator.",
+			Example: `{{ list "a" "b" "c" | join ", " }}`,
+		},
+	},
+	{
+		Name: "contains",
+		Fn:   tmplfunc.Contains,
+		Meta: tmplfunc.Meta{
+			Doc:     "contains reports whether the second argument contains the first.",
+			Example: `{{ if contains "cat" "concatenate" }}yes{{ end }}`,
+		},
+	},
+	{
+		Name: "hasPrefix",
+		Fn:   tmplfunc.HasPrefix,
+		Meta: tmplfunc.Meta{
+			Doc:     "hasPrefix reports whether the second argument begins with the first.",
+			Example: `{{ if hasPrefix "http" .Params.url }}link{{ end }}`,
+		},
+	},
+	{
+		Name: "hasSuffix",
+		Fn:   tmplfunc.HasSuffix,
+		Meta: tmplfunc.Meta{
+			Doc:     "hasSuffix reports whether the second argument ends with the first.",
+			Example: `{{ if hasSuffix ".md" .Params.path }}markdown{{ end }}`,
+		},
+	},
+	{
+		Name: "truncate",
+		Fn:   tmplfunc.Truncate,
+		Meta: tmplfunc.Meta{
+			Doc:     "truncate returns the first n characters of a string, or the last -n characters if n is negative.",
+			Example: `{{ "hello world" | truncate 5 }}`,
+		},
+	},
+	{
+		Name: "trunc",
+		Fn:   tmplfunc.Truncate,
+		Meta: tmplfunc.Meta{
+			Doc:     "trunc is an alias for truncate.",
+			Example: `{{ "hello world" | trunc 5 }}`,
+		},
+	},
+	{
+		Name: "indent",
+		Fn:   tmplfunc.Indent,
+		Meta: tmplfunc.Meta{
+			Doc:     "indent indents every line of a string with n spaces.",
+			Example: `{{ file "snippet.yaml" | indent 4 }}`,
+		},
+	},
+	{
+		Name: "nindent",
+		Fn:   tmplfunc.NIndent,
+		Meta: tmplfunc.Meta{
+			Doc:     "nindent is like indent, but adds a newline before the string.",
+			Example: `{{ file "snippet.yaml" | nindent 4 }}`,
+		},
+	},
+	{
+		Name: "quote",
+		Fn:   tmplfunc.Quote,
+		Meta: tmplfunc.Meta{
+			Doc:     "quote wraps each argument in double quotes, escaping as needed.",
+			Example: `{{ .Params.name | quote }}`,
+		},
+	},
+	{
+		Name: "squote",
+		Fn:   tmplfunc.SQuote,
+		Meta: tmplfunc.Meta{
+			Doc:     "squote wraps each argument in single quotes.",
+			Example: `{{ .Params.name | squote }}`,
+		},
+	},
+}
@@ -0,0 +1,44 @@
+package tmpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// textBuiltins are the functions text/template predefines.
+var textBuiltins = []string{
+	"and", "call", "html", "index", "slice", "js", "len", "not", "or", "print", "printf",
+	"println", "urlquery", "eq", "ge", "gt", "le", "lt", "ne",
+}
+
+func TestFuncsKeepTextBuiltins(t *testing.T) {
+	funcs := New().funcs()
+	for _, name := range textBuiltins {
+		if _, ok := funcs[name]; ok {
+			t.Errorf("%s replaces text/template's builtin", name)
+		}
+	}
+}
+
+func TestBuiltinSlice(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{{ slice "hello" 1 3 }}`, "el"},
+		{`{{ $x := list 1 2 3 4 }}{{ slice $x 1 2 3 | len }}`, "1"},
+		{`{{ $x := splitList "," "a,b,c" }}{{ index (slice $x 1) 0 }}`, "b"},
+	}
+
+	for _, test := range tests {
+		buf := bytes.Buffer{}
+		if err := New().Execute(&buf, strings.NewReader(test.in)); err != nil {
+			t.Errorf("%s: %s", test.in, err)
+			continue
+		}
+
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// regexFuncs match and replace regular expressions. Compiled patterns are cached for the life of the process.
+var regexFuncs = []tmplfunc.Func{
+	{
+		Name: "regexMatch",
+		Fn:   tmplfunc.RegexMatch,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexMatch reports whether a string contains a match of a regular expression.",
+			Example: `{{ if regexMatch "^[0-9]+$" .Params.id }}numeric{{ end }}`,
+		},
+	},
+	{
+		Name: "regexFind",
+		Fn:   tmplfunc.RegexFind,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexFind returns the first match of a regular expression in a string.",
+			Example: `{{ regexFind "[0-9]+" "abc123def" }}`,
+		},
+	},
+	{
+		Name: "regexFindAll",
+		Fn:   tmplfunc.RegexFindAll,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexFindAll returns up to n matches of a regular expression in a string, or every match if n is -1.",
+			Example: `{{ regexFindAll "[0-9]+" "a1b22c333" -1 }}`,
+		},
+	},
+	{
+		Name: "regexReplace",
+		Fn:   tmplfunc.RegexReplace,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexReplace replaces every match of a regular expression in a string, expanding $1-style references to submatches.",
+			Example: `{{ regexReplace "(\\w+)@(\\w+)" "me@example" "$2 at $1" }}`,
+		},
+	},
+	{
+		Name: "regexReplaceAll",
+		Fn:   tmplfunc.RegexReplace,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexReplaceAll is an alias for regexReplace.",
+			Example: `{{ regexReplaceAll "\\s+" "a  b   c" " " }}`,
+		},
+	},
+	{
+		Name: "regexSplit",
+		Fn:   tmplfunc.RegexSplit,
+		Meta: tmplfunc.Meta{
+			Doc:     "regexSplit splits a string around matches of a regular expression, returning at most n substrings, or every substring if n is -1.",
+			Example: `{{ regexSplit "\\s*,\\s*" "a , b,c" -1 }}`,
+		},
+	},
+}
+
+// textFuncs transform text and HTML.
+var textFuncs = []tmplfunc.Func{
+	{
+		Name: "slugify",
+		Fn:   tmplfunc.Slugify,
+		Meta: tmplfunc.Meta{
+			Doc:     "slugify converts a string into a lowercase, URL-friendly slug, transliterating accented, Greek and Cyrillic letters.",
+			Example: `{{ "Crème Brûlée à la Française" | slugify }}`,
+		},
+	},
+	{
+		Name: "wordCount",
+		Fn:   tmplfunc.WordCount,
+		Meta: tmplfunc.Meta{
+			Doc:     "wordCount returns the number of words in a string, ignoring HTML tags.",
+			Example: `{{ wordCount .Content }}`,
+		},
+	},
+	{
+		Name: "readingTime",
+		Fn:   tmplfunc.ReadingTime,
+		Meta: tmplfunc.Meta{
+			Doc:     "readingTime returns the number of minutes it takes to read a string at 200 words per minute, or the provided rate.",
+			Example: `{{ readingTime .Content }} min read`,
+		},
+	},
+	{
+		Name: "stripHTML",
+		Fn:   tmplfunc.StripHTML,
+		Meta: tmplfunc.Meta{
+			Doc:     "stripHTML removes HTML tags and comments, and the contents of script and style elements, and unescapes character references.",
+			Example: `{{ "<p>Hello <b>world</b></p>" | stripHTML }}`,
+		},
+	},
+	{
+		Name: "excerpt",
+		Fn:   tmplfunc.Excerpt,
+		Meta: tmplfunc.Meta{
+			Doc:     "excerpt returns the beginning of some HTML, keeping at most n characters of text and closing any open elements. Only simple formatting elements and links are kept.",
+			Example: `{{ .Content | excerpt 200 }}`,
+		},
+	},
+	{
+		Name: "wrap",
+		Fn:   tmplfunc.Wrap,
+		Meta: tmplfunc.Meta{
+			Doc:     "wrap wraps a string so that no line is longer than the provided width.",
+			Example: `{{ .Params.description | wrap 80 }}`,
+		},
+	},
+}
@@ -0,0 +1,23 @@
+package tmpl
+
+import "github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+
+// xmlFuncs help write well-formed XML.
+var xmlFuncs = []tmplfunc.Func{
+	{
+		Name: "xmlEscape",
+		Fn:   tmplfunc.XMLEscape,
+		Meta: tmplfunc.Meta{
+			Doc:     "xmlEscape escapes the characters which are special in XML text and attribute values.",
+			Example: `<title>{{ .Page.title | xmlEscape }}</title>`,
+		},
+	},
+	{
+		Name: "cdata",
+		Fn:   tmplfunc.CDATA,
+		Meta: tmplfunc.Meta{
+			Doc:     "cdata wraps a string in a CDATA section, so it can contain markup without escaping.",
+			Example: `<content:encoded>{{ .Content | cdata }}</content:encoded>`,
+		},
+	},
+}
@@ -5,15 +5,19 @@ import (
 	html "html/template"
 	"io"
 
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 	"github.com/pkg/errors"
-	"github.com/tdewolff/minify"
-	htmlminify "github.com/tdewolff/minify/html"
 )
 
 type HTMLTmpl struct {
 	*Tmpl
 
 	Minify bool
+
+	// ScriptHashes and StyleHashes are the CSP hashes of the inline scripts and styles
+	// in the output, after minification. They're set by Execute.
+	ScriptHashes []string
+	StyleHashes  []string
 }
 
 func (t *HTMLTmpl) WithMinify(m bool) *HTMLTmpl {
@@ -42,11 +46,14 @@ func (t *HTMLTmpl) Execute(out io.Writer, in io.Reader) error {
 		by := buf.Bytes()
 		buf.Reset()
 
-		m := minify.New()
-		hm := htmlminify.DefaultMinifier
-		hm.KeepDocumentTags = true
+		if err := minifier(t.minify).Minify("text/html", &buf, bytes.NewReader(by)); err != nil {
+			return errors.Wrap(err, "minify (text/html)")
+		}
+	}
 
-		hm.Minify(m, &buf, bytes.NewReader(by), nil)
+	t.ScriptHashes, t.StyleHashes = tmplfunc.InlineHashes(buf.Bytes())
+	if t.csp != nil {
+		t.csp.Set(t.cspDoc, t.ScriptHashes, t.StyleHashes)
 	}
 
 	if _, err := io.Copy(out, &buf); err != nil {
@@ -4,15 +4,35 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
 	text "text/template"
 
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 	"github.com/pkg/errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 type JSONTmpl struct {
 	*Tmpl
 
 	Minify bool
+
+	// Indent is the indentation used when the output isn't minified. It defaults to four
+	// spaces.
+	Indent string
+
+	// Schema, if set, is the path to a JSON Schema the output must match, relative to the
+	// base directory.
+	Schema string
+
+	// SortKeys re-serializes the output with each object's keys sorted.
+	SortKeys bool
+
+	// TrailingNewline ends the output with exactly one newline.
+	TrailingNewline bool
 }
 
 func (t *JSONTmpl) WithMinify(m bool) *JSONTmpl {
@@ -20,6 +40,26 @@ func (t *JSONTmpl) WithMinify(m bool) *JSONTmpl {
 	return t
 }
 
+func (t *JSONTmpl) WithIndent(indent string) *JSONTmpl {
+	t.Indent = indent
+	return t
+}
+
+func (t *JSONTmpl) WithSchema(path string) *JSONTmpl {
+	t.Schema = path
+	return t
+}
+
+func (t *JSONTmpl) WithSortKeys(s bool) *JSONTmpl {
+	t.SortKeys = s
+	return t
+}
+
+func (t *JSONTmpl) WithTrailingNewline(n bool) *JSONTmpl {
+	t.TrailingNewline = n
+	return t
+}
+
 func (t *JSONTmpl) Execute(out io.Writer, in io.Reader) error {
 	buf := bytes.Buffer{}
 	if _, err := io.Copy(&buf, in); err != nil {
@@ -34,7 +74,26 @@ func (t *JSONTmpl) Execute(out io.Writer, in io.Reader) error {
 	buf.Reset()
 
 	if err := tmpl.Execute(&buf, t); err != nil {
-		errors.Wrap(err, "execute template")
+		return errors.Wrap(err, "execute template")
+	}
+
+	if err := validateJSON(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "json: validate")
+	}
+
+	if t.Schema != "" {
+		if err := t.validateSchema(buf.Bytes()); err != nil {
+			return errors.Wrapf(err, "json: schema (path: %s)", t.Schema)
+		}
+	}
+
+	if t.SortKeys {
+		by, err := sortJSON(buf.Bytes())
+		if err != nil {
+			return errors.Wrap(err, "json: sort keys")
+		}
+
+		buf = *bytes.NewBuffer(by)
 	}
 
 	dst := bytes.Buffer{}
@@ -43,14 +102,120 @@ func (t *JSONTmpl) Execute(out io.Writer, in io.Reader) error {
 			return errors.Wrap(err, "json: compact")
 		}
 	} else {
-		if err := json.Indent(&dst, buf.Bytes(), "", "    "); err != nil {
+		indent := t.Indent
+		if indent == "" {
+			indent = "    "
+		}
+
+		if err := json.Indent(&dst, buf.Bytes(), "", indent); err != nil {
 			return errors.Wrap(err, "json: indent")
 		}
 	}
 
+	if t.TrailingNewline {
+		dst = *bytes.NewBuffer(append(bytes.TrimRight(dst.Bytes(), " \t\r\n"), '\n'))
+	}
+
 	if _, err := io.Copy(out, &dst); err != nil {
 		return errors.Wrap(err, "io: copy (output)")
 	}
 
 	return nil
 }
+
+// validateSchema returns an error listing each location in doc which doesn't match the
+// template's schema. The schema file is marked as updateable.
+func (t *JSONTmpl) validateSchema(doc []byte) error {
+	path := tmplfunc.FSPath(t.FS(), t.BaseDir(), t.Schema)
+
+	by, err := fs.ReadFile(t.FS(), path)
+	if err != nil {
+		return errors.Wrap(err, "fs: read file")
+	}
+
+	t.Ref(path)
+
+	abs, _ := filepath.Abs(path)
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(abs, bytes.NewReader(by)); err != nil {
+		return errors.Wrap(err, "load")
+	}
+
+	schema, err := c.Compile(abs)
+	if err != nil {
+		return errors.Wrap(err, "compile")
+	}
+
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return errors.Wrap(err, "decode")
+	}
+
+	err = schema.Validate(v)
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	failures := []string{}
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			loc := e.InstanceLocation
+			if loc == "" {
+				loc = "/"
+			}
+
+			failures = append(failures, loc+": "+e.Message)
+		}
+
+		for _, c := range e.Causes {
+			walk(c)
+		}
+	}
+	walk(verr)
+
+	sort.Strings(failures)
+	return errors.Errorf("document doesn't match the schema:\n\t%s", strings.Join(failures, "\n\t"))
+}
+
+// validateJSON returns an error, with the line it occurred on, if doc isn't valid JSON.
+func validateJSON(doc []byte) error {
+	var v interface{}
+	err := json.Unmarshal(doc, &v)
+
+	var serr *json.SyntaxError
+	if errors.As(err, &serr) {
+		line := bytes.Count(doc[:serr.Offset], []byte("\n")) + 1
+		return errors.Errorf("line %d: %s", line, serr.Error())
+	}
+
+	return err
+}
+
+// sortJSON returns doc with each object's keys sorted. Numbers and strings are kept as
+// they are.
+func sortJSON(doc []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(doc))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.Buffer{}
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,92 @@
+package tmpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestJSONTmpl(t *testing.T) {
+	const in = `{"b": {{ add 1 1 }}, "a": [1.50, "<x>"], "c": {"z": null, "y": true}}`
+
+	tests := []struct {
+		name string
+		tmpl func() *JSONTmpl
+		want string
+	}{
+		{"default indent", func() *JSONTmpl { return New().JSON() }, "{\n    \"b\": 2,\n    \"a\": [\n        1.50,\n        \"<x>\"\n    ],\n    \"c\": {\n        \"z\": null,\n        \"y\": true\n    }\n}"},
+		{"indent", func() *JSONTmpl { return New().JSON().WithIndent("\t").WithSortKeys(true) }, "{\n\t\"a\": [\n\t\t1.50,\n\t\t\"<x>\"\n\t],\n\t\"b\": 2,\n\t\"c\": {\n\t\t\"y\": true,\n\t\t\"z\": null\n\t}\n}"},
+		{"minify", func() *JSONTmpl { return New().JSON().WithMinify(true) }, `{"b":2,"a":[1.50,"<x>"],"c":{"z":null,"y":true}}`},
+		{"minify sorted", func() *JSONTmpl { return New().JSON().WithMinify(true).WithSortKeys(true) }, `{"a":[1.50,"<x>"],"b":2,"c":{"y":true,"z":null}}`},
+		{"trailing newline", func() *JSONTmpl { return New().JSON().WithMinify(true).WithTrailingNewline(true) }, `{"b":2,"a":[1.50,"<x>"],"c":{"z":null,"y":true}}` + "\n"},
+	}
+
+	for _, test := range tests {
+		buf := bytes.Buffer{}
+		if err := test.tmpl().Execute(&buf, strings.NewReader(in)); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestJSONTmplInvalid(t *testing.T) {
+	err := New().JSON().Execute(&bytes.Buffer{}, strings.NewReader("{\n\"a\": 1,\n}"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Execute = %v, want an error on line 3", err)
+	}
+}
+
+func TestJSONTmplSchema(t *testing.T) {
+	fsys := fstest.MapFS{
+		"site/schemas/post.json": {Data: []byte(`{
+			"type": "object",
+			"required": ["title"],
+			"properties": {
+				"title": {"type": "string"},
+				"tags": {"type": "array", "items": {"type": "string"}}
+			}
+		}`)},
+	}
+
+	tests := []struct {
+		in   string
+		errs []string
+	}{
+		{`{"title": "a", "tags": ["x"]}`, nil},
+		{`{"tags": ["x", 1]}`, []string{"/: missing properties: 'title'", "/tags/1: expected string, but got number"}},
+	}
+
+	for _, test := range tests {
+		tm := New().WithFS(fsys).WithBaseDir("site")
+		err := tm.JSON().WithSchema("schemas/post.json").Execute(&bytes.Buffer{}, strings.NewReader(test.in))
+
+		if test.errs == nil {
+			if err != nil {
+				t.Errorf("%s: %s", test.in, err)
+			}
+		} else if err == nil {
+			t.Errorf("%s: expected an error", test.in)
+		} else {
+			for _, want := range test.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("%s: error %q doesn't contain %q", test.in, err, want)
+				}
+			}
+		}
+
+		if refs := tm.Refs(); len(refs) != 1 || refs[0] != "site/schemas/post.json" {
+			t.Errorf("%s: refs = %q, want the schema", test.in, refs)
+		}
+	}
+
+	err := New().WithFS(fsys).JSON().WithSchema("missing.json").Execute(&bytes.Buffer{}, strings.NewReader("{}"))
+	if err == nil {
+		t.Errorf("missing schema: expected an error")
+	}
+}
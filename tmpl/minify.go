@@ -0,0 +1,97 @@
+package tmpl
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/tdewolff/minify/v2"
+	"github.com/tdewolff/minify/v2/css"
+	"github.com/tdewolff/minify/v2/html"
+	"github.com/tdewolff/minify/v2/js"
+	"github.com/tdewolff/minify/v2/json"
+	"github.com/tdewolff/minify/v2/svg"
+	"github.com/tdewolff/minify/v2/xml"
+)
+
+// MinifyOptions configures the minifiers used when a template's output is minified. The
+// zero value minifies as much as possible, except that HTML documents keep their html,
+// head and body tags.
+type MinifyOptions struct {
+	// KeepComments keeps HTML and SVG comments.
+	KeepComments bool
+
+	// KeepConditionalComments keeps IE conditional comments in HTML.
+	KeepConditionalComments bool
+
+	// KeepDefaultAttrVals keeps HTML attributes which are set to their default value.
+	KeepDefaultAttrVals bool
+
+	// KeepEndTags keeps optional HTML end tags.
+	KeepEndTags bool
+
+	// KeepQuotes keeps the quotes around HTML attribute values.
+	KeepQuotes bool
+
+	// KeepWhitespace keeps whitespace between HTML and XML elements, collapsed to a
+	// single space.
+	KeepWhitespace bool
+
+	// KeepVarNames keeps JavaScript variable names instead of shortening them.
+	KeepVarNames bool
+
+	// Precision is the number of significant digits numbers in CSS, JS, SVG and JSON
+	// are rounded to. Zero keeps every digit.
+	Precision int
+}
+
+var (
+	jsMediaType   = regexp.MustCompile(`^(application|text)/(x-)?(java|ecma)script$`)
+	jsonMediaType = regexp.MustCompile(`[/+]json$`)
+	xmlMediaType  = regexp.MustCompile(`[/+]xml$`)
+)
+
+// minifier returns a minifier for HTML, CSS, JavaScript, JSON, SVG and XML configured by
+// opts. The HTML and SVG minifiers also minify inline styles, scripts and SVGs.
+func minifier(opts MinifyOptions) *minify.M {
+	m := minify.New()
+
+	m.Add("text/html", &html.Minifier{
+		KeepComments:            opts.KeepComments,
+		KeepConditionalComments: opts.KeepConditionalComments,
+		KeepDefaultAttrVals:     opts.KeepDefaultAttrVals,
+		KeepDocumentTags:        true,
+		KeepEndTags:             opts.KeepEndTags,
+		KeepQuotes:              opts.KeepQuotes,
+		KeepWhitespace:          opts.KeepWhitespace,
+	})
+	m.Add("text/css", &css.Minifier{
+		Precision: opts.Precision,
+	})
+	m.AddRegexp(jsMediaType, &js.Minifier{
+		Precision:    opts.Precision,
+		KeepVarNames: opts.KeepVarNames,
+	})
+	m.AddRegexp(jsonMediaType, &json.Minifier{
+		Precision: opts.Precision,
+	})
+	m.Add("image/svg+xml", &svg.Minifier{
+		KeepComments: opts.KeepComments,
+		Precision:    opts.Precision,
+	})
+	m.AddRegexp(xmlMediaType, &xml.Minifier{
+		KeepWhitespace: opts.KeepWhitespace,
+	})
+
+	return m
+}
+
+// Minify minifies by as the provided media type, using the template's minify options.
+func (t *Tmpl) Minify(mediaType string, by []byte) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := minifier(t.minify).Minify(mediaType, &buf, bytes.NewReader(by)); err != nil {
+		return nil, errors.Wrapf(err, "minify (%s)", mediaType)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package tmpl
+
+import (
+	"bytes"
+	"io"
+	text "text/template"
+
+	"github.com/pkg/errors"
+)
+
+// MinifyTmpl is a text template whose output is a stylesheet, script or other document
+// which can be minified.
+type MinifyTmpl struct {
+	*Tmpl
+
+	// MediaType is the type of the output, like "text/css", which selects the minifier.
+	MediaType string
+
+	Minify bool
+}
+
+func (t *MinifyTmpl) WithMinify(m bool) *MinifyTmpl {
+	t.Minify = m
+	return t
+}
+
+func (t *MinifyTmpl) Execute(out io.Writer, in io.Reader) error {
+	buf := bytes.Buffer{}
+	if _, err := io.Copy(&buf, in); err != nil {
+		return errors.Wrap(err, "io: copy (input)")
+	}
+
+	tmpl, err := text.New("output").Funcs(t.funcs()).Delims(t.leftDelim, t.rightDelim).Parse(buf.String())
+	if err != nil {
+		return errors.Wrap(err, "compile template")
+	}
+
+	buf.Reset()
+
+	if err := tmpl.Execute(&buf, t); err != nil {
+		return errors.Wrap(err, "execute template")
+	}
+
+	if t.Minify {
+		by := buf.Bytes()
+		buf = bytes.Buffer{}
+
+		if err := minifier(t.minify).Minify(t.MediaType, &buf, bytes.NewReader(by)); err != nil {
+			return errors.Wrapf(err, "minify (%s)", t.MediaType)
+		}
+	}
+
+	if _, err := io.Copy(out, &buf); err != nil {
+		return errors.Wrap(err, "io: copy (output)")
+	}
+
+	return nil
+}
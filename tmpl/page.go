@@ -0,0 +1,72 @@
+package tmpl
+
+import (
+	"bytes"
+	html "html/template"
+
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var frontMatterDelim = []byte("---")
+
+// SplitFrontMatter separates a document's YAML front matter, delimited by "---" lines at
+// the start of the document, from its body. Documents without front matter have no
+// values.
+func SplitFrontMatter(src []byte) (map[string]interface{}, []byte, error) {
+	page := map[string]interface{}{}
+
+	rest := bytes.TrimPrefix(src, []byte("\ufeff"))
+	line, rest, _ := bytes.Cut(rest, []byte("\n"))
+	if !bytes.Equal(bytes.TrimSpace(line), frontMatterDelim) {
+		return page, src, nil
+	}
+
+	for i := 0; i < len(rest); {
+		end := bytes.IndexByte(rest[i:], '\n')
+		if end < 0 {
+			end = len(rest) - i
+		}
+
+		if bytes.Equal(bytes.TrimSpace(rest[i:i+end]), frontMatterDelim) {
+			if err := yaml.Unmarshal(rest[:i], &page); err != nil {
+				return nil, nil, errors.Wrap(err, "yaml: decode front matter")
+			}
+
+			if page == nil {
+				page = map[string]interface{}{}
+			}
+
+			return page, rest[min(i+end+1, len(rest)):], nil
+		}
+
+		i += end + 1
+	}
+
+	return nil, nil, errors.New("front matter isn't closed")
+}
+
+// ParsePage splits a Markdown document into its front matter and body, and converts the
+// body to HTML.
+func ParsePage(src []byte, opts tmplfunc.MarkdownOptions) (map[string]interface{}, html.HTML, error) {
+	page, body, err := SplitFrontMatter(src)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := tmplfunc.ConvertMarkdown(opts, body)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "convert markdown")
+	}
+
+	return page, content, nil
+}
+
+// WithPage sets the values available to the template as .Page and .Content, usually
+// from ParsePage.
+func (t *Tmpl) WithPage(page map[string]interface{}, content html.HTML) *Tmpl {
+	t.Page = page
+	t.Content = content
+	return t
+}
@@ -0,0 +1,68 @@
+package tmpl
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		page map[string]interface{}
+		body string
+	}{
+		{"front matter", "---\ntitle: Hi\ntags: [a]\n---\n# Body\n", map[string]interface{}{"title": "Hi", "tags": []interface{}{"a"}}, "# Body\n"},
+		{"crlf", "---\r\ntitle: Hi\r\n---\r\nBody", map[string]interface{}{"title": "Hi"}, "Body"},
+		{"bom", "\ufeff---\ntitle: Hi\n---\nBody", map[string]interface{}{"title": "Hi"}, "Body"},
+		{"empty", "---\n---\nBody", map[string]interface{}{}, "Body"},
+		{"at end", "---\ntitle: Hi\n---", map[string]interface{}{"title": "Hi"}, ""},
+		{"none", "# Body\n---\n", map[string]interface{}{}, "# Body\n---\n"},
+		{"delimiter later", "Body\n---\ntitle: Hi\n---\n", map[string]interface{}{}, "Body\n---\ntitle: Hi\n---\n"},
+	}
+
+	for _, test := range tests {
+		page, body, err := SplitFrontMatter([]byte(test.src))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(page, test.page) {
+			t.Errorf("%s: front matter = %v, want %v", test.name, page, test.page)
+		}
+
+		if string(body) != test.body {
+			t.Errorf("%s: body = %q, want %q", test.name, body, test.body)
+		}
+	}
+
+	for _, src := range []string{"---\ntitle: Hi\n", "---\n: [\n---\n", "---\n- a\n---\n"} {
+		if _, _, err := SplitFrontMatter([]byte(src)); err == nil {
+			t.Errorf("SplitFrontMatter(%q): expected an error", src)
+		}
+	}
+}
+
+func TestPageLayout(t *testing.T) {
+	page, content, err := ParsePage([]byte("---\ntitle: Hello & welcome\n---\n# Heading\n\n<b>raw</b>\n"), tmplfunc.MarkdownOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layout := `<title>{{ .Page.title }}</title>{{ .Content }}`
+
+	buf := bytes.Buffer{}
+	if err := New().WithPage(page, content).HTML().Execute(&buf, strings.NewReader(layout)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "<title>Hello &amp; welcome</title><h1>Heading</h1>\n<p><b>raw</b></p>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Execute = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package tmpl
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrOutsideSandbox is returned when a template tries to read a file outside of the
+// directories allowed by the sandbox.
+var ErrOutsideSandbox = errors.New("path is outside the sandbox")
+
+// sandboxFS is an fs.FS backed by the operating system's filesystem which only allows
+// access to files inside a set of root directories. Relative paths are resolved against
+// the first root; paths which escape the roots, either with .. or through a symlink,
+// are rejected.
+type sandboxFS struct {
+	roots []string
+}
+
+func newSandboxFS(base string, allow []string) *sandboxFS {
+	s := &sandboxFS{}
+	for _, root := range append([]string{base}, allow...) {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(base, root)
+		}
+
+		root, _ = filepath.Abs(root)
+		if real, err := filepath.EvalSymlinks(root); err == nil {
+			root = real
+		}
+
+		s.roots = append(s.roots, root)
+	}
+
+	return s
+}
+
+// abs returns the absolute path name resolves to, without checking whether it's allowed.
+func (s *sandboxFS) abs(name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+
+	return filepath.Join(s.roots[0], name)
+}
+
+// resolve returns the real path of name, or an error if it's outside the sandbox.
+func (s *sandboxFS) resolve(op, name string) (string, error) {
+	path := s.abs(name)
+	if !s.allowed(path) {
+		return "", &fs.PathError{Op: op, Path: name, Err: ErrOutsideSandbox}
+	}
+
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !s.allowed(real) {
+		return "", &fs.PathError{Op: op, Path: name, Err: ErrOutsideSandbox}
+	}
+
+	return real, nil
+}
+
+func (s *sandboxFS) allowed(path string) bool {
+	for _, root := range s.roots {
+		if path == root || strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OSPaths marks sandboxFS as a tmplfunc.OSFS.
+func (s *sandboxFS) OSPaths() {}
+
+func (s *sandboxFS) Open(name string) (fs.File, error) {
+	path, err := s.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *sandboxFS) Stat(name string) (fs.FileInfo, error) {
+	path, err := s.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Stat(path)
+}
+
+func (s *sandboxFS) ReadFile(name string) ([]byte, error) {
+	path, err := s.resolve("read", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
@@ -0,0 +1,65 @@
+package tmpl
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSandbox(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"site", "shared", "secret"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(root, dir, "a.txt"), []byte(dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Symlink(filepath.Join(root, "secret"), filepath.Join(root, "site", "link")); err != nil {
+		t.Skipf("symlink: %s", err)
+	}
+
+	s := newSandboxFS(filepath.Join(root, "site"), []string{"../shared"})
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"a.txt", "site"},
+		{filepath.Join(root, "site", "a.txt"), "site"},
+		{filepath.Join("..", "shared", "a.txt"), "shared"},
+		{filepath.Join(root, "shared", "a.txt"), "shared"},
+	}
+
+	for _, test := range tests {
+		by, err := fs.ReadFile(s, test.name)
+		if err != nil {
+			t.Errorf("ReadFile(%s): %s", test.name, err)
+			continue
+		}
+
+		if string(by) != test.want {
+			t.Errorf("ReadFile(%s) = %q, want %q", test.name, by, test.want)
+		}
+	}
+
+	for _, name := range []string{
+		filepath.Join("..", "secret", "a.txt"),
+		filepath.Join(root, "secret", "a.txt"),
+		filepath.Join("link", "a.txt"),
+		filepath.Join("..", "site-other", "a.txt"),
+	} {
+		if _, err := fs.ReadFile(s, name); !errors.Is(err, ErrOutsideSandbox) {
+			t.Errorf("ReadFile(%s) = %v, want %v", name, err, ErrOutsideSandbox)
+		}
+
+		if _, err := fs.Stat(s, name); !errors.Is(err, ErrOutsideSandbox) {
+			t.Errorf("Stat(%s) = %v, want %v", name, err, ErrOutsideSandbox)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package tmpl
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Line endings for TextOptions.
+const (
+	LineEndingsLF   = "lf"
+	LineEndingsCRLF = "crlf"
+)
+
+// generatedHeader is the notice added by TextOptions.Header. It follows the convention
+// recognized by Go's tools, and by linters and editors for other languages.
+const generatedHeader = "Code generated by tmpl. DO NOT EDIT."
+
+// TextOptions configures how plain text output is cleaned up. The zero value leaves
+// the output unchanged.
+type TextOptions struct {
+	// TrimTrailingWhitespace removes spaces and tabs from the end of each line.
+	TrimTrailingWhitespace bool
+
+	// CollapseBlankLines replaces runs of blank lines with a single blank line, and
+	// removes blank lines from the start of the output.
+	CollapseBlankLines bool
+
+	// LineEndings is LineEndingsLF or LineEndingsCRLF. If it's empty, line endings are
+	// kept as the template wrote them.
+	LineEndings string
+
+	// TrailingNewline ends the output with exactly one line ending.
+	TrailingNewline bool
+
+	// Header adds a comment saying the file is generated to the top of the output, after
+	// any #! line or XML declaration, using the comment syntax for the output's file
+	// extension. Formats without comments, like JSON and CSV, don't get a header.
+	Header bool
+}
+
+// commentSyntax maps file extensions, and names without one, to the start and end of a
+// comment.
+var commentSyntax = map[string][2]string{}
+
+func init() {
+	for syntax, exts := range map[[2]string][]string{
+		{"#", ""}: {
+			".sh", ".bash", ".zsh", ".fish", ".py", ".rb", ".pl", ".r", ".yaml", ".yml",
+			".toml", ".conf", ".cfg", ".env", ".properties", ".tf", ".tfvars", ".hcl",
+			".mk", ".cmake", ".nix", ".service", ".gitignore", ".dockerignore",
+			".gitattributes", ".editorconfig", "makefile", "dockerfile", "procfile",
+			"caddyfile", "gemfile", "brewfile",
+		},
+		{"//", ""}: {
+			".go", ".js", ".mjs", ".cjs", ".ts", ".jsx", ".tsx", ".c", ".h", ".cc", ".cpp",
+			".hpp", ".cs", ".java", ".kt", ".kts", ".scala", ".swift", ".rs", ".dart",
+			".scss", ".less", ".proto", ".jsonc", ".json5", ".groovy", ".gradle", ".zig",
+		},
+		{"/*", " */"}:    {".css"},
+		{"<!--", " -->"}: {".html", ".htm", ".xml", ".svg", ".md", ".markdown", ".vue", ".plist"},
+		{"--", ""}:       {".sql", ".lua", ".hs", ".elm"},
+		{";", ""}:        {".ini", ".el", ".clj", ".lisp", ".scm", ".asm"},
+		{"%", ""}:        {".tex", ".erl", ".m"},
+		{"REM", ""}:      {".bat", ".cmd"},
+		{`"`, ""}:        {".vim"},
+	} {
+		for _, ext := range exts {
+			commentSyntax[ext] = syntax
+		}
+	}
+}
+
+// WithTextOptions configures how plain text output is cleaned up. name is the output's
+// file name, which selects the comment syntax for the header.
+func (t *Tmpl) WithTextOptions(opts TextOptions, name string) *Tmpl {
+	t.text = opts
+	t.textName = name
+	return t
+}
+
+// textHeader returns the generated file notice as a comment for the file name. It
+// returns false if the file's format has no known comment syntax, like .json, .csv or
+// .txt, since any header would become part of the content.
+func textHeader(name string) (string, bool) {
+	base := strings.ToLower(filepath.Base(name))
+
+	syntax, ok := commentSyntax[filepath.Ext(base)]
+	if !ok {
+		syntax, ok = commentSyntax[base]
+	}
+	if !ok {
+		return "", false
+	}
+
+	return syntax[0] + " " + generatedHeader + syntax[1], true
+}
+
+// apply returns doc cleaned up as configured. name is the output's file name.
+func (o TextOptions) apply(doc []byte, name string) ([]byte, error) {
+	eol := ""
+	switch o.LineEndings {
+	case "":
+	case LineEndingsLF:
+		eol = "\n"
+	case LineEndingsCRLF:
+		eol = "\r\n"
+	default:
+		return nil, errors.Errorf("unknown line endings %q", o.LineEndings)
+	}
+
+	type line struct {
+		text string
+		eol  string
+	}
+
+	lines := []line{}
+	for _, s := range strings.SplitAfter(string(doc), "\n") {
+		if s == "" {
+			continue
+		}
+
+		l := line{text: s}
+		if strings.HasSuffix(s, "\r\n") {
+			l.text, l.eol = strings.TrimSuffix(s, "\r\n"), "\r\n"
+		} else if strings.HasSuffix(s, "\n") {
+			l.text, l.eol = strings.TrimSuffix(s, "\n"), "\n"
+		}
+
+		lines = append(lines, l)
+	}
+
+	if o.TrimTrailingWhitespace {
+		for i := range lines {
+			lines[i].text = strings.TrimRight(lines[i].text, " \t")
+		}
+	}
+
+	if o.CollapseBlankLines {
+		collapsed := lines[:0]
+		blank := true
+		for _, l := range lines {
+			if strings.TrimSpace(l.text) == "" {
+				if blank {
+					continue
+				}
+				blank = true
+			} else {
+				blank = false
+			}
+
+			collapsed = append(collapsed, l)
+		}
+
+		lines = collapsed
+	}
+
+	if o.Header {
+		if header, ok := textHeader(name); ok {
+			// The header's line ending matches the first line's.
+			h := line{text: header, eol: "\n"}
+			if len(lines) > 0 && lines[0].eol != "" {
+				h.eol = lines[0].eol
+			}
+
+			// The header goes after a #! line or an XML declaration, which must come
+			// first.
+			i := 0
+			if len(lines) > 0 && (strings.HasPrefix(lines[0].text, "#!") || strings.HasPrefix(lines[0].text, "<?xml")) {
+				// Anything after the XML declaration on its line moves below the header.
+				if end := strings.Index(lines[0].text, "?>") + 2; strings.HasPrefix(lines[0].text, "<?xml") && end > 1 && end < len(lines[0].text) {
+					rest := line{text: strings.TrimLeft(lines[0].text[end:], " \t"), eol: lines[0].eol}
+					lines[0].text = lines[0].text[:end]
+					lines = append(lines[:1], append([]line{rest}, lines[1:]...)...)
+				}
+
+				i = 1
+				lines[0].eol = h.eol
+			}
+
+			lines = append(lines[:i], append([]line{h}, lines[i:]...)...)
+		}
+	}
+
+	if o.TrailingNewline {
+		for len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1].text) == "" {
+			lines = lines[:len(lines)-1]
+		}
+
+		// The added line ending matches the ones the file already uses.
+		if len(lines) > 0 && lines[len(lines)-1].eol == "" {
+			lines[len(lines)-1].eol = "\n"
+			for _, l := range lines {
+				if l.eol != "" {
+					lines[len(lines)-1].eol = l.eol
+					break
+				}
+			}
+		}
+	}
+
+	buf := bytes.Buffer{}
+	for _, l := range lines {
+		buf.WriteString(l.text)
+		if l.eol != "" && eol != "" {
+			buf.WriteString(eol)
+		} else {
+			buf.WriteString(l.eol)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package tmpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextOptions(t *testing.T) {
+	const header = "Code generated by tmpl. DO NOT EDIT."
+
+	tests := []struct {
+		name string
+		opts TextOptions
+		file string
+		in   string
+		want string
+	}{
+		{"zero value", TextOptions{}, "a.txt", "a  \n\n\nb", "a  \n\n\nb"},
+		{"trim", TextOptions{TrimTrailingWhitespace: true}, "a.txt", "a \t\nb  \r\n", "a\nb\r\n"},
+		{"collapse", TextOptions{CollapseBlankLines: true}, "a.txt", "\n\na\n\n\n\nb\n", "a\n\nb\n"},
+		{"collapse whitespace lines", TextOptions{CollapseBlankLines: true}, "a.txt", "a\n  \n\t\nb", "a\n  \nb"},
+		{"lf", TextOptions{LineEndings: LineEndingsLF}, "a.txt", "a\r\nb\nc", "a\nb\nc"},
+		{"crlf", TextOptions{LineEndings: LineEndingsCRLF}, "a.txt", "a\r\nb\nc", "a\r\nb\r\nc"},
+		{"trailing newline", TextOptions{TrailingNewline: true}, "a.txt", "a\n\n\n", "a\n"},
+		{"trailing newline added", TextOptions{TrailingNewline: true}, "a.txt", "a", "a\n"},
+		{"trailing newline keeps crlf", TextOptions{TrailingNewline: true}, "a.txt", "a\r\nb", "a\r\nb\r\n"},
+		{"trailing newline converted", TextOptions{TrailingNewline: true, LineEndings: LineEndingsCRLF}, "a.txt", "a\nb", "a\r\nb\r\n"},
+		{"header", TextOptions{Header: true}, "run.sh", "echo hi\n", "# " + header + "\necho hi\n"},
+		{"header after #!", TextOptions{Header: true}, "run.sh", "#!/bin/sh\r\necho hi\r\n", "#!/bin/sh\r\n# " + header + "\r\necho hi\r\n"},
+		{"header by name", TextOptions{Header: true}, "Makefile", "all:\n", "# " + header + "\nall:\n"},
+		{"header for go", TextOptions{Header: true}, "gen.go", "package x\n", "// " + header + "\npackage x\n"},
+		{"header for css", TextOptions{Header: true}, "a.css", "a{}\n", "/* " + header + " */\na{}\n"},
+		{"header on empty output", TextOptions{Header: true}, "a.py", "", "# " + header + "\n"},
+		{"header after xml declaration", TextOptions{Header: true}, "feed.xml", "<?xml version=\"1.0\"?>\n<a/>\n", "<?xml version=\"1.0\"?>\n<!-- " + header + " -->\n<a/>\n"},
+		{"header after inline xml declaration", TextOptions{Header: true}, "feed.xml", "<?xml version=\"1.0\"?> <a/>", "<?xml version=\"1.0\"?>\n<!-- " + header + " -->\n<a/>"},
+		{"no header for json", TextOptions{Header: true}, "data.json", "{}\n", "{}\n"},
+		{"no header for csv", TextOptions{Header: true}, "data.csv", "a,b\n", "a,b\n"},
+		{"no header for txt", TextOptions{Header: true}, "notes.txt", "hi\n", "hi\n"},
+	}
+
+	for _, test := range tests {
+		got, err := test.opts.apply([]byte(test.in), test.file)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if _, err := (TextOptions{LineEndings: "cr"}).apply([]byte("a"), "a.txt"); err == nil {
+		t.Errorf("unknown line endings: expected an error")
+	}
+}
+
+func TestExecuteWithTextOptions(t *testing.T) {
+	tm := New().WithTextOptions(TextOptions{TrimTrailingWhitespace: true, TrailingNewline: true}, "out.txt")
+
+	buf := bytes.Buffer{}
+	if err := tm.Execute(&buf, strings.NewReader("{{ range list 1 2 }}{{ . }}  \n{{ end }}\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "1\n2\n"; got != want {
+		t.Errorf("Execute = %q, want %q", got, want)
+	}
+}
@@ -2,12 +2,16 @@ package tmpl
 
 import (
 	"bytes"
+	html "html/template"
 	"io"
+	"io/fs"
 	"os"
 	"runtime"
+	"strings"
 	text "text/template"
 	"time"
 
+	"github.com/jimmysawczuk/tmpl/tmpl/tmplfunc"
 	"github.com/pkg/errors"
 )
 
@@ -27,17 +31,41 @@ const (
 type Tmpl struct {
 	Hostname string
 	GoEnv    goEnv
+	Params   map[string]interface{}
+
+	// Page and Content are the front matter and converted body of the Markdown page
+	// rendered into a layout.
+	Page    map[string]interface{}
+	Content html.HTML
 
 	mode    Mode
-	in      *os.File
-	out     *os.File
+	in      io.Reader
+	out     io.Writer
+	fsys    fs.FS
 	baseDir string
 
 	leftDelim  string
 	rightDelim string
 
-	now     time.Time
-	envVars map[string]string
+	now      time.Time
+	envVars  map[string]string
+	commands map[string]tmplfunc.Command
+
+	locale       string
+	translations *tmplfunc.Translations
+
+	markdown tmplfunc.MarkdownOptions
+	minify   MinifyOptions
+	assets   tmplfunc.AssetOptions
+
+	csp    *tmplfunc.CSPHashes
+	cspDoc string
+
+	text     TextOptions
+	textName string
+
+	extraFuncs    text.FuncMap
+	disabledFuncs []string
 
 	refs map[string]struct{}
 }
@@ -54,6 +82,7 @@ func New() *Tmpl {
 		},
 		leftDelim:  "{{",
 		rightDelim: "}}",
+		fsys:       osFS{},
 		now:        time.Now(),
 		refs:       map[string]struct{}{},
 	}
@@ -61,12 +90,35 @@ func New() *Tmpl {
 	return t
 }
 
-func (t *Tmpl) WithIO(in, out *os.File) *Tmpl {
+func (t *Tmpl) WithIO(in io.Reader, out io.Writer) *Tmpl {
 	t.in = in
 	t.out = out
 	return t
 }
 
+// WithFS sets the filesystem used by every function that reads files, like file, inline
+// and getJSON. By default, files are read from the operating system's filesystem relative
+// to the working directory.
+func (t *Tmpl) WithFS(fsys fs.FS) *Tmpl {
+	t.fsys = fsys
+	return t
+}
+
+// WithSandbox restricts file access to the base directory set with WithBaseDir, plus any
+// additional allowed directories. Relative paths are resolved against the base directory
+// rather than the working directory, and paths which escape the allowed directories,
+// either with .. or through a symlink, are rejected. WithSandbox replaces any filesystem
+// set with WithFS, so it should be called after WithBaseDir.
+func (t *Tmpl) WithSandbox(allow ...string) *Tmpl {
+	base := t.baseDir
+	if base == "" {
+		base = "."
+	}
+
+	t.fsys = newSandboxFS(base, allow)
+	return t
+}
+
 func (t *Tmpl) WithBaseDir(dir string) *Tmpl {
 	t.baseDir = dir
 	return t
@@ -85,18 +137,32 @@ func (t *Tmpl) WithDelims(left, right string) *Tmpl {
 	return t
 }
 
-func (t *Tmpl) In() *os.File {
+func (t *Tmpl) In() io.Reader {
 	return t.in
 }
 
-func (t *Tmpl) Out() *os.File {
+func (t *Tmpl) Out() io.Writer {
 	return t.out
 }
 
+func (t *Tmpl) FS() fs.FS {
+	return t.fsys
+}
+
 func (t *Tmpl) BaseDir() string {
 	return t.baseDir
 }
 
+// Now returns the time the template was created, which the now function returns.
+func (t *Tmpl) Now() time.Time {
+	return t.now
+}
+
+// EnvVars returns the variables set with WithEnv.
+func (t *Tmpl) EnvVars() map[string]string {
+	return t.envVars
+}
+
 func (t *Tmpl) IsProduction() bool {
 	return t.mode == ModeProduction
 }
@@ -113,12 +179,178 @@ func (t *Tmpl) JSON() *JSONTmpl {
 	}
 }
 
+// CSS returns a template whose output is a stylesheet.
+func (t *Tmpl) CSS() *MinifyTmpl {
+	return &MinifyTmpl{
+		Tmpl:      t,
+		MediaType: "text/css",
+	}
+}
+
+// JS returns a template whose output is a script.
+func (t *Tmpl) JS() *MinifyTmpl {
+	return &MinifyTmpl{
+		Tmpl:      t,
+		MediaType: "application/javascript",
+	}
+}
+
+// SVG returns a template whose output is an SVG image.
+func (t *Tmpl) SVG() *MinifyTmpl {
+	return &MinifyTmpl{
+		Tmpl:      t,
+		MediaType: "image/svg+xml",
+	}
+}
+
+// XML returns a template whose output is an XML document. The output is checked to be
+// well-formed.
+func (t *Tmpl) XML() *XMLTmpl {
+	return &XMLTmpl{
+		Tmpl: t,
+	}
+}
+
+// YAML returns a template whose output is a YAML document, or a stream of them. The
+// output is checked to parse.
+func (t *Tmpl) YAML() *DataTmpl {
+	return &DataTmpl{
+		Tmpl:   t,
+		Format: FormatYAML,
+	}
+}
+
+// TOML returns a template whose output is a TOML document. The output is checked to
+// parse.
+func (t *Tmpl) TOML() *DataTmpl {
+	return &DataTmpl{
+		Tmpl:   t,
+		Format: FormatTOML,
+	}
+}
+
+// CSV returns a template whose output is a CSV document. The output is checked to parse
+// and have the same number of fields in every record.
+func (t *Tmpl) CSV() *DataTmpl {
+	return &DataTmpl{
+		Tmpl:   t,
+		Format: FormatCSV,
+	}
+}
+
+// WithMinifyOptions configures the minifiers used when the template's output is
+// minified.
+func (t *Tmpl) WithMinifyOptions(opts MinifyOptions) *Tmpl {
+	t.minify = opts
+	return t
+}
+
+// WithEnv sets variables which take precedence over the process environment in the
+// env function. Keys are matched case-insensitively.
 func (t *Tmpl) WithEnv(m map[string]string) *Tmpl {
-	t.envVars = m
+	t.envVars = make(map[string]string, len(m))
+	for k, v := range m {
+		t.envVars[strings.ToLower(k)] = v
+	}
+	return t
+}
+
+// WithCommands adds a template function for each of the provided commands.
+// See tmplfunc.Exec.
+func (t *Tmpl) WithCommands(m map[string]tmplfunc.Command) *Tmpl {
+	t.commands = m
+	return t
+}
+
+// WithFuncs adds the provided functions to the template, overriding any registered
+// function with the same name.
+func (t *Tmpl) WithFuncs(m text.FuncMap) *Tmpl {
+	if t.extraFuncs == nil {
+		t.extraFuncs = text.FuncMap{}
+	}
+
+	for name, fn := range m {
+		t.extraFuncs[name] = fn
+	}
+	return t
+}
+
+// WithoutFuncs removes the named functions from the template.
+func (t *Tmpl) WithoutFuncs(names ...string) *Tmpl {
+	t.disabledFuncs = append(t.disabledFuncs, names...)
+	return t
+}
+
+// WithParams sets the values available to the template as .Params.
+func (t *Tmpl) WithParams(m map[string]interface{}) *Tmpl {
+	t.Params = m
+	return t
+}
+
+// WithLocale sets the locale the template is rendered for, like "en" or "pt-BR". It's
+// available to the template as .Locale and used by t and the formatting functions.
+func (t *Tmpl) WithLocale(locale string) *Tmpl {
+	t.locale = locale
+	return t
+}
+
+// WithTranslations sets the message catalogs used by the t function.
+func (t *Tmpl) WithTranslations(tr *tmplfunc.Translations) *Tmpl {
+	t.translations = tr
+	return t
+}
+
+// Locale returns the locale set with WithLocale.
+func (t *Tmpl) Locale() string {
+	return t.locale
+}
+
+// Translations returns the catalogs set with WithTranslations.
+func (t *Tmpl) Translations() *tmplfunc.Translations {
+	return t.translations
+}
+
+// WithMarkdown sets the options used by the markdown functions.
+func (t *Tmpl) WithMarkdown(opts tmplfunc.MarkdownOptions) *Tmpl {
+	t.markdown = opts
+	return t
+}
+
+// MarkdownOptions returns the options set with WithMarkdown.
+func (t *Tmpl) MarkdownOptions() tmplfunc.MarkdownOptions {
+	return t.markdown
+}
+
+// WithAssets sets the options used by the asset function.
+func (t *Tmpl) WithAssets(opts tmplfunc.AssetOptions) *Tmpl {
+	t.assets = opts
+	return t
+}
+
+// AssetOptions returns the options set with WithAssets.
+func (t *Tmpl) AssetOptions() tmplfunc.AssetOptions {
+	return t.assets
+}
+
+// WithCSPHashes sets the collection the hashes of the template's inline scripts and
+// styles are recorded in, under the name doc, when it's executed as HTML. It's also used
+// by the cspHashes function.
+func (t *Tmpl) WithCSPHashes(c *tmplfunc.CSPHashes, doc string) *Tmpl {
+	t.csp = c
+	t.cspDoc = doc
 	return t
 }
 
+// CSPHashes returns the collection set with WithCSPHashes.
+func (t *Tmpl) CSPHashes() *tmplfunc.CSPHashes {
+	return t.csp
+}
+
 func (t *Tmpl) Ref(path string) error {
+	if s, ok := t.fsys.(*sandboxFS); ok {
+		path = s.abs(path)
+	}
+
 	t.refs[path] = struct{}{}
 	return nil
 }
@@ -146,9 +378,26 @@ func (t *Tmpl) Execute(out io.Writer, in io.Reader) error {
 
 	buf.Reset()
 
-	if err := tmpl.Execute(out, t); err != nil {
+	if t.text == (TextOptions{}) {
+		if err := tmpl.Execute(out, t); err != nil {
+			return errors.Wrap(err, "execute template")
+		}
+
+		return nil
+	}
+
+	if err := tmpl.Execute(&buf, t); err != nil {
 		return errors.Wrap(err, "execute template")
 	}
 
+	by, err := t.text.apply(buf.Bytes(), t.textName)
+	if err != nil {
+		return errors.Wrap(err, "text")
+	}
+
+	if _, err := out.Write(by); err != nil {
+		return errors.Wrap(err, "io: write (output)")
+	}
+
 	return nil
 }
@@ -0,0 +1,215 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Fingerprint styles for AssetOptions.
+const (
+	// FingerprintFilename copies each asset to a file with its hash in the name, like
+	// /css/style.3f2a9c1b.css.
+	FingerprintFilename = "filename"
+
+	// FingerprintQuery adds the hash to the asset's URL, like /css/style.css?v=3f2a9c1b.
+	FingerprintQuery = "query"
+
+	// FingerprintNone leaves the URL unchanged.
+	FingerprintNone = "none"
+)
+
+// AssetOptions configures the asset function.
+type AssetOptions struct {
+	// Dir is the public directory asset paths are relative to. If it's empty, asset
+	// only cleans the path it's given.
+	Dir string
+
+	// Fingerprint is how asset URLs are cache-busted in production mode. It defaults to
+	// FingerprintFilename.
+	Fingerprint string
+
+	// CDN is prefixed to asset URLs in production mode, like "https://cdn.example.com".
+	CDN string
+
+	// Manifest, if set, records the URL returned for each asset.
+	Manifest *AssetManifest
+
+	// Bundles are the bundles available to the bundle and inlineBundle functions by name.
+	Bundles map[string]Bundle
+}
+
+type Asseter interface {
+	AssetOptions() AssetOptions
+}
+
+type AssetRefer interface {
+	FilesystemRefer
+	Moder
+	Asseter
+}
+
+// AssetManifest maps asset paths to the URLs asset returned for them. It's safe for
+// concurrent use, so one manifest can be shared by every template in a build.
+type AssetManifest struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// NewAssetManifest returns an empty AssetManifest.
+func NewAssetManifest() *AssetManifest {
+	return &AssetManifest{
+		entries: map[string]string{},
+	}
+}
+
+// Add records that the asset at path is served from url.
+func (m *AssetManifest) Add(path, url string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[path] = url
+}
+
+// Entries returns a copy of the manifest's entries.
+func (m *AssetManifest) Entries() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tbr := make(map[string]string, len(m.entries))
+	for k, v := range m.entries {
+		tbr[k] = v
+	}
+
+	return tbr
+}
+
+// Len returns the number of entries in the manifest.
+func (m *AssetManifest) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// WriteFile writes the manifest to the provided path as a JSON object.
+func (m *AssetManifest) WriteFile(path string) error {
+	by, err := json.MarshalIndent(m.Entries(), "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "json: marshal")
+	}
+
+	if err := os.WriteFile(path, append(by, '\n'), 0o644); err != nil {
+		return errors.Wrapf(err, "write file (path: %s)", path)
+	}
+
+	return nil
+}
+
+// Asset returns a function which returns the URL for the asset at the provided path,
+// relative to the public directory. It returns an error if the asset doesn't exist, and
+// marks it as updateable. In production mode, the URL is fingerprinted with a hash of
+// the asset's contents and prefixed with the CDN, if there is one.
+func Asset(a AssetRefer) func(string) (string, error) {
+	return func(p string) (string, error) {
+		if strings.Contains(p, "://") || strings.HasPrefix(p, "//") {
+			return p, nil
+		}
+
+		p = path.Clean(p)
+
+		opts := a.AssetOptions()
+		if opts.Dir == "" {
+			return p, nil
+		}
+
+		file, err := publicFile(opts.Dir, p)
+		if err != nil {
+			return "", errors.Wrap(err, "asset")
+		}
+
+		by, err := fs.ReadFile(a.FS(), file)
+		if err != nil {
+			return "", errors.Wrapf(err, "asset: read (path: %s)", p)
+		}
+
+		a.Ref(file)
+
+		return assetURL(opts, a.IsProduction(), p, by)
+	}
+}
+
+// assetURL returns the URL for the asset at p, with the contents by. In production, the
+// URL is fingerprinted and prefixed with the CDN. The URL is recorded in the manifest.
+func assetURL(opts AssetOptions, production bool, p string, by []byte) (string, error) {
+	url := p
+	if production {
+		hash := sha256.Sum256(by)
+		sum := hex.EncodeToString(hash[:4])
+
+		switch opts.Fingerprint {
+		case FingerprintNone:
+		case FingerprintQuery:
+			url = p + "?v=" + sum
+		case "", FingerprintFilename:
+			ext := path.Ext(p)
+			url = strings.TrimSuffix(p, ext) + "." + sum + ext
+
+			file, err := publicFile(opts.Dir, url)
+			if err != nil {
+				return "", errors.Wrap(err, "asset")
+			}
+
+			if err := writeIfChanged(file, by); err != nil {
+				return "", errors.Wrapf(err, "asset: copy (path: %s)", p)
+			}
+		default:
+			return "", errors.Errorf("asset: unknown fingerprint style %q", opts.Fingerprint)
+		}
+
+		if opts.CDN != "" {
+			url = strings.TrimSuffix(opts.CDN, "/") + "/" + strings.TrimPrefix(url, "/")
+		}
+	}
+
+	if opts.Manifest != nil {
+		opts.Manifest.Add(p, url)
+	}
+
+	return url, nil
+}
+
+// publicFile returns the path of the file at p, a path relative to the public directory
+// dir. It returns an error if p would be outside dir.
+func publicFile(dir string, p string) (string, error) {
+	if c := path.Clean(p); c == ".." || strings.HasPrefix(c, "../") {
+		return "", errors.Errorf("path is outside the public directory (path: %s)", p)
+	}
+
+	file := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+p)))
+
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("path is outside the public directory (path: %s)", p)
+	}
+
+	return file, nil
+}
+
+// writeIfChanged writes by to path, unless it already has the same contents.
+func writeIfChanged(path string, by []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, by) {
+		return nil
+	}
+
+	return os.WriteFile(path, by, 0o644)
+}
@@ -0,0 +1,39 @@
+package tmplfunc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPublicFile(t *testing.T) {
+	dir := filepath.FromSlash("/srv/public")
+
+	tests := []struct {
+		p    string
+		want string
+	}{
+		{"/css/style.css", "/srv/public/css/style.css"},
+		{"css/style.css", "/srv/public/css/style.css"},
+		{"/css/../js/app.js", "/srv/public/js/app.js"},
+		{"/../css/style.css", "/srv/public/css/style.css"},
+		{"/", "/srv/public"},
+	}
+
+	for _, test := range tests {
+		got, err := publicFile(dir, test.p)
+		if err != nil {
+			t.Errorf("publicFile(%q): %s", test.p, err)
+			continue
+		}
+
+		if want := filepath.FromSlash(test.want); got != want {
+			t.Errorf("publicFile(%q) = %q, want %q", test.p, got, want)
+		}
+	}
+
+	for _, p := range []string{"..", "../tmpl.config.json", "css/../../secret", "./../x"} {
+		if got, err := publicFile(dir, p); err == nil {
+			t.Errorf("publicFile(%q) = %q, expected an error", p, got)
+		}
+	}
+}
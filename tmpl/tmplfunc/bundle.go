@@ -0,0 +1,271 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle is a set of stylesheets or scripts which are concatenated into one file.
+type Bundle struct {
+	// Type is "css" or "js". It defaults to the extension of Out, or else the extension
+	// shared by every file.
+	Type string
+
+	// Out is the path the bundle is written to, relative to the public directory. It
+	// defaults to /bundles/<name>.<type>.
+	Out string
+
+	// Files are the paths of the bundled files, relative to the public directory.
+	Files []string
+}
+
+type Minifier interface {
+	Minify(mediaType string, by []byte) ([]byte, error)
+}
+
+type BundleRefer interface {
+	AssetRefer
+	Minifier
+}
+
+// bundleMediaTypes maps bundle types to the media type they're minified as.
+var bundleMediaTypes = map[string]string{
+	"css": "text/css",
+	"js":  "application/javascript",
+}
+
+// bundleArgs returns the bundle described by the arguments to bundle or inlineBundle:
+// either the name of a configured bundle, or a type followed by the files to bundle.
+func bundleArgs(opts AssetOptions, args []string) (string, Bundle, error) {
+	if len(args) == 1 {
+		b, ok := opts.Bundles[args[0]]
+		if !ok {
+			return "", Bundle{}, errors.Errorf("unknown bundle %q", args[0])
+		}
+
+		b, err := b.Resolve(args[0])
+		if err != nil {
+			return "", Bundle{}, err
+		}
+
+		return args[0], b, nil
+	}
+
+	if len(args) < 2 {
+		return "", Bundle{}, errors.New("expected a bundle name, or a type and at least one file")
+	}
+
+	b := Bundle{Type: args[0], Files: args[1:]}
+
+	// Unnamed bundles are named after the files they contain, so each set of files gets
+	// its own output.
+	hash := sha256.Sum256([]byte(strings.Join(b.Files, "\n")))
+	name := hex.EncodeToString(hash[:4])
+	b.Out = "/bundles/" + name + "." + b.Type
+
+	return name, b, nil
+}
+
+// Resolve returns the bundle with the provided name with its type and output path
+// filled in. It returns an error if the type can't be determined or isn't css or js.
+func (b Bundle) Resolve(name string) (Bundle, error) {
+	if b.Type == "" {
+		b.Type = strings.TrimPrefix(path.Ext(b.Out), ".")
+	}
+
+	if b.Type == "" && len(b.Files) > 0 {
+		b.Type = strings.TrimPrefix(path.Ext(b.Files[0]), ".")
+		for _, f := range b.Files[1:] {
+			if strings.TrimPrefix(path.Ext(f), ".") != b.Type {
+				b.Type = ""
+				break
+			}
+		}
+	}
+
+	if _, ok := bundleMediaTypes[b.Type]; !ok {
+		if b.Type == "" {
+			return b, errors.Errorf("bundle %s: can't tell its type; set type to css or js", name)
+		}
+
+		return b, errors.Errorf("bundle %s: unknown type %q (expected css or js)", name, b.Type)
+	}
+
+	if b.Out == "" {
+		b.Out = "/bundles/" + name + "." + b.Type
+	}
+
+	return b, nil
+}
+
+// build concatenates the bundle's files, marking each as updateable, and minifies the
+// result in production mode. Scripts are separated by semicolons, and relative URLs in
+// stylesheets are rewritten relative to the public directory, since the bundle is
+// written to (or inlined in) a different place than the files.
+func (b Bundle) build(r BundleRefer) ([]byte, error) {
+	mediaType, ok := bundleMediaTypes[b.Type]
+	if !ok {
+		return nil, errors.Errorf("unknown bundle type %q (expected css or js)", b.Type)
+	}
+
+	dir := r.AssetOptions().Dir
+
+	buf := bytes.Buffer{}
+	for _, f := range b.Files {
+		file := filepath.FromSlash(path.Clean(f))
+		if dir != "" {
+			var err error
+			if file, err = publicFile(dir, f); err != nil {
+				return nil, err
+			}
+		}
+
+		by, err := fs.ReadFile(r.FS(), file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read (path: %s)", f)
+		}
+
+		r.Ref(file)
+
+		if b.Type == "css" {
+			by = rewriteCSSURLs(by, path.Dir(path.Clean("/"+f)))
+		}
+
+		buf.Write(by)
+		if len(by) > 0 && by[len(by)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+
+		// A script which doesn't end in a semicolon would otherwise run into the next
+		// one if it starts with ( or [.
+		if b.Type == "js" && len(by) > 0 {
+			buf.WriteString(";\n")
+		}
+	}
+
+	if !r.IsProduction() {
+		return buf.Bytes(), nil
+	}
+
+	by, err := r.Minify(mediaType, buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "minify")
+	}
+
+	return by, nil
+}
+
+// BundleFunc returns a function which concatenates a bundle of stylesheets or scripts,
+// writes it to the public directory and returns its URL, like asset. The bundle is
+// either the name of a configured bundle or a type ("css" or "js") followed by the
+// files to bundle, relative to the public directory. Each file is marked as
+// updateable. In production mode, the bundle is minified and fingerprinted.
+func BundleFunc(r BundleRefer) func(...string) (string, error) {
+	return func(args ...string) (string, error) {
+		opts := r.AssetOptions()
+
+		name, b, err := bundleArgs(opts, args)
+		if err != nil {
+			return "", errors.Wrap(err, "bundle")
+		}
+
+		if opts.Dir == "" {
+			return "", errors.Errorf("bundle: %s: no public directory to write to", name)
+		}
+
+		by, err := b.build(r)
+		if err != nil {
+			return "", errors.Wrapf(err, "bundle: %s", name)
+		}
+
+		out := path.Clean("/" + b.Out)
+		file, err := publicFile(opts.Dir, b.Out)
+		if err != nil {
+			return "", errors.Wrapf(err, "bundle: %s", name)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return "", errors.Wrapf(err, "bundle: %s: mkdir", name)
+		}
+
+		if err := writeIfChanged(file, by); err != nil {
+			return "", errors.Wrapf(err, "bundle: %s: write (path: %s)", name, out)
+		}
+
+		return assetURL(opts, r.IsProduction(), out, by)
+	}
+}
+
+// InlineBundle returns a function which concatenates a bundle like bundle does, but
+// returns its contents instead of writing it, for inlining critical CSS or scripts.
+// Stylesheets are returned as template.CSS and scripts as template.JS, so they aren't
+// escaped in html blocks.
+func InlineBundle(r BundleRefer) func(...string) (interface{}, error) {
+	return func(args ...string) (interface{}, error) {
+		name, b, err := bundleArgs(r.AssetOptions(), args)
+		if err != nil {
+			return nil, errors.Wrap(err, "inlineBundle")
+		}
+
+		by, err := b.build(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "inlineBundle: %s", name)
+		}
+
+		if b.Type == "js" {
+			return template.JS(by), nil
+		}
+
+		return template.CSS(by), nil
+	}
+}
+
+var (
+	cssURLPattern    = regexp.MustCompile(`url\(\s*(?:"([^"]*)"|'([^']*)'|([^'")\s]*))\s*\)`)
+	cssImportPattern = regexp.MustCompile(`@import\s+(?:"([^"]*)"|'([^']*)')`)
+	urlSchemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// rewriteCSSURLs rewrites the relative URLs in a stylesheet's url() values and @import
+// rules as absolute paths, resolved against dir, the stylesheet's directory in the
+// public directory.
+func rewriteCSSURLs(by []byte, dir string) []byte {
+	resolve := func(pattern *regexp.Regexp, m []byte) []byte {
+		sub := pattern.FindSubmatchIndex(m)
+
+		for i := 2; i < len(sub); i += 2 {
+			if sub[i] < 0 {
+				continue
+			}
+
+			u := string(m[sub[i]:sub[i+1]])
+			if u == "" || strings.HasPrefix(u, "/") || strings.HasPrefix(u, "#") || urlSchemePattern.MatchString(u) {
+				return m
+			}
+
+			// Keep any query or fragment, like the ones font URLs use.
+			p, suffix := u, ""
+			if j := strings.IndexAny(u, "?#"); j >= 0 {
+				p, suffix = u[:j], u[j:]
+			}
+
+			abs := path.Join(dir, p) + suffix
+			return append(append(append([]byte{}, m[:sub[i]]...), abs...), m[sub[i+1]:]...)
+		}
+
+		return m
+	}
+
+	by = cssURLPattern.ReplaceAllFunc(by, func(m []byte) []byte { return resolve(cssURLPattern, m) })
+	return cssImportPattern.ReplaceAllFunc(by, func(m []byte) []byte { return resolve(cssImportPattern, m) })
+}
@@ -0,0 +1,112 @@
+package tmplfunc
+
+import (
+	"html/template"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundleResolve(t *testing.T) {
+	tests := []struct {
+		in       Bundle
+		typ, out string
+	}{
+		{Bundle{Type: "js", Files: []string{"/a.mjs"}}, "js", "/bundles/site.js"},
+		{Bundle{Out: "/css/all.css", Files: []string{"/a.scss"}}, "css", "/css/all.css"},
+		{Bundle{Files: []string{"/css/a.css", "/css/b.css"}}, "css", "/bundles/site.css"},
+	}
+
+	for _, test := range tests {
+		got, err := test.in.Resolve("site")
+		if err != nil {
+			t.Errorf("Resolve(%+v): %s", test.in, err)
+			continue
+		}
+
+		if got.Type != test.typ || got.Out != test.out {
+			t.Errorf("Resolve(%+v) = type %q, out %q, want %q, %q", test.in, got.Type, got.Out, test.typ, test.out)
+		}
+	}
+
+	for _, b := range []Bundle{
+		{},
+		{Files: []string{"/a.css", "/b.js"}},
+		{Type: "scss", Files: []string{"/a.scss"}},
+		{Out: "/bundle.txt"},
+	} {
+		if _, err := b.Resolve("site"); err == nil {
+			t.Errorf("Resolve(%+v): expected an error", b)
+		}
+	}
+}
+
+// testBundler is a BundleRefer which reads files from an fs.FS in local mode.
+type testBundler struct {
+	fsys fs.FS
+	refs []string
+}
+
+func (b *testBundler) FS() fs.FS                  { return b.fsys }
+func (b *testBundler) BaseDir() string            { return "" }
+func (b *testBundler) Ref(p string) error         { b.refs = append(b.refs, p); return nil }
+func (b *testBundler) IsProduction() bool         { return false }
+func (b *testBundler) AssetOptions() AssetOptions { return AssetOptions{} }
+func (b *testBundler) Minify(_ string, by []byte) ([]byte, error) {
+	return by, nil
+}
+
+func TestInlineBundle(t *testing.T) {
+	r := &testBundler{fsys: fstest.MapFS{
+		"js/a.js":           {Data: []byte("var a = 1")},
+		"js/b.js":           {Data: []byte("(function () {})()\n")},
+		"css/site.css":      {Data: []byte(`body { background: url(img/bg.png) }`)},
+		"css/vendor/ui.css": {Data: []byte(`@font-face { src: url("../fonts/ui.woff2?v=2#x") }`)},
+	}}
+
+	got, err := InlineBundle(r)("js", "js/a.js", "js/b.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := template.JS("var a = 1\n;\n(function () {})()\n;\n"); got != want {
+		t.Errorf("inlineBundle js = %q, want %q", got, want)
+	}
+
+	got, err = InlineBundle(r)("css", "css/site.css", "css/vendor/ui.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := template.CSS("body { background: url(/css/img/bg.png) }\n" + `@font-face { src: url("/css/fonts/ui.woff2?v=2#x") }` + "\n")
+	if got != want {
+		t.Errorf("inlineBundle css = %q, want %q", got, want)
+	}
+
+	if want := []string{"js/a.js", "js/b.js", "css/site.css", "css/vendor/ui.css"}; !reflect.DeepEqual(r.refs, want) {
+		t.Errorf("refs = %v, want %v", r.refs, want)
+	}
+}
+
+func TestRewriteCSSURLs(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`a { b: url(x.png) }`, `a { b: url(/css/x.png) }`},
+		{`a { b: url( 'x.png' ) }`, `a { b: url( '/css/x.png' ) }`},
+		{`a { b: url("../img/x.png") }`, `a { b: url("/img/x.png") }`},
+		{`a { b: url(/img/x.png) }`, `a { b: url(/img/x.png) }`},
+		{`a { b: url(https://cdn.example.com/x.png) }`, `a { b: url(https://cdn.example.com/x.png) }`},
+		{`a { b: url(data:image/png;base64,AAAA) }`, `a { b: url(data:image/png;base64,AAAA) }`},
+		{`a { filter: url(#blur) }`, `a { filter: url(#blur) }`},
+		{`@import "reset.css";`, `@import "/css/reset.css";`},
+		{`@import url(print.css) print;`, `@import url(/css/print.css) print;`},
+	}
+
+	for _, test := range tests {
+		if got := string(rewriteCSSURLs([]byte(test.in), "/css")); got != test.want {
+			t.Errorf("rewriteCSSURLs(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package tmplfunc
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Where returns the elements of the provided list whose field matches a condition.
+// It takes 3 or 4 arguments:
+//
+//	Where(list, field, value)
+//	Where(list, field, op, value)
+//
+// The field is a dotted path into each element, like "author.name". op is one of ==, !=,
+// <, <=, >, >=, in, "not in" or contains, and defaults to ==.
+func Where(v interface{}, field string, args ...interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var op string
+	var value interface{}
+	switch len(args) {
+	case 1:
+		op, value = "==", args[0]
+	case 2:
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, errors.Errorf("where: expected an operator, got %T", args[0])
+		}
+		op, value = s, args[1]
+	default:
+		return nil, errors.New("where: expected a value, or an operator and a value")
+	}
+
+	match, err := matcher(op, value)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := []interface{}{}
+	for _, el := range list {
+		fv, _ := fieldValue(el, field)
+		if match(fv) {
+			tbr = append(tbr, el)
+		}
+	}
+
+	return tbr, nil
+}
+
+func matcher(op string, value interface{}) (func(interface{}) bool, error) {
+	switch strings.ToLower(op) {
+	case "=", "==", "eq":
+		return func(fv interface{}) bool { return equal(fv, value) }, nil
+	case "!=", "<>", "ne":
+		return func(fv interface{}) bool { return !equal(fv, value) }, nil
+	case "<", "lt":
+		return func(fv interface{}) bool { c, ok := compare(fv, value); return ok && c < 0 }, nil
+	case "<=", "le":
+		return func(fv interface{}) bool { c, ok := compare(fv, value); return ok && c <= 0 }, nil
+	case ">", "gt":
+		return func(fv interface{}) bool { c, ok := compare(fv, value); return ok && c > 0 }, nil
+	case ">=", "ge":
+		return func(fv interface{}) bool { c, ok := compare(fv, value); return ok && c >= 0 }, nil
+	case "in", "not in":
+		list, err := toSlice(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "where: %s", op)
+		}
+
+		want := strings.ToLower(op) == "in"
+		return func(fv interface{}) bool {
+			for _, el := range list {
+				if equal(fv, el) {
+					return want
+				}
+			}
+			return !want
+		}, nil
+	case "contains":
+		return func(fv interface{}) bool {
+			if s, ok := fv.(string); ok {
+				return strings.Contains(s, toString(value))
+			}
+
+			list, err := toSlice(fv)
+			if err != nil {
+				return false
+			}
+
+			for _, el := range list {
+				if equal(el, value) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	default:
+		return nil, errors.Errorf("where: unknown operator %q", op)
+	}
+}
+
+// SortBy returns a copy of the provided list sorted by the value at field in each element.
+// The order is "asc" (the default) or "desc". Values of different types are ordered
+// booleans, numbers (including numeric strings), times, then other strings and values.
+// Elements without the field sort first in either order.
+func SortBy(v interface{}, field string, order ...string) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := false
+	if len(order) > 0 {
+		switch strings.ToLower(order[0]) {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, errors.Errorf("sortBy: unknown order %q", order[0])
+		}
+	}
+
+	tbr := append([]interface{}{}, list...)
+	sort.SliceStable(tbr, func(i, j int) bool {
+		a, _ := fieldValue(tbr[i], field)
+		b, _ := fieldValue(tbr[j], field)
+
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+
+		c := sortCompare(a, b)
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	return tbr, nil
+}
+
+// sortRank ranks the types SortBy orders: booleans, numbers, times, strings and anything
+// else.
+func sortRank(v interface{}) int {
+	switch v.(type) {
+	case bool:
+		return 0
+	case time.Time:
+		return 2
+	case string:
+		if _, err := toFloat(v); err == nil {
+			return 1
+		}
+		return 3
+	}
+
+	if _, err := toFloat(v); err == nil {
+		return 1
+	}
+
+	return 4
+}
+
+// sortCompare orders a and b for SortBy, first by their type's rank and then by value.
+// Unlike compare, it's a total order, even over values of mixed types.
+func sortCompare(a, b interface{}) int {
+	ra, rb := sortRank(a), sortRank(b)
+	if ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+
+	switch ra {
+	case 0:
+		ab, bb := a.(bool), b.(bool)
+		switch {
+		case ab == bb:
+			return 0
+		case !ab:
+			return -1
+		}
+		return 1
+	case 1:
+		af, _ := toFloat(a)
+		bf, _ := toFloat(b)
+		return cmp.Compare(af, bf)
+	case 2:
+		return a.(time.Time).Compare(b.(time.Time))
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// GroupBy groups the elements of the provided list by the value at field, returning a map
+// from each value (as a string) to the elements with that value.
+func GroupBy(v interface{}, field string) (map[string]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := map[string]interface{}{}
+	for _, el := range list {
+		fv, _ := fieldValue(el, field)
+		key := ""
+		if fv != nil {
+			key = toString(fv)
+		}
+
+		group, _ := tbr[key].([]interface{})
+		tbr[key] = append(group, el)
+	}
+
+	return tbr, nil
+}
+
+// CountBy counts the elements of the provided list by the value at field, returning a map
+// from each value (as a string) to the number of elements with that value.
+func CountBy(v interface{}, field string) (map[string]int, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := map[string]int{}
+	for _, el := range list {
+		fv, _ := fieldValue(el, field)
+		key := ""
+		if fv != nil {
+			key = toString(fv)
+		}
+
+		tbr[key]++
+	}
+
+	return tbr, nil
+}
+
+// Limit returns at most the first n elements of the provided list.
+func Limit(v interface{}, n interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := toInt(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return list[:max(min(i, len(list)), 0)], nil
+}
+
+// Offset returns the elements of the provided list after the first n.
+func Offset(v interface{}, n interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := toInt(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return list[max(min(i, len(list)), 0):], nil
+}
+
+// Pluck returns the value at field in each element of the provided list. Elements without
+// the field are skipped.
+func Pluck(v interface{}, field string) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := []interface{}{}
+	for _, el := range list {
+		if fv, ok := fieldValue(el, field); ok {
+			tbr = append(tbr, fv)
+		}
+	}
+
+	return tbr, nil
+}
+
+// Sum returns the sum of the elements of the provided list, or of the value at field in
+// each element if a field is provided.
+func Sum(v interface{}, field ...string) (interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(field) > 0 {
+		if list, err = Pluck(list, field[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(list) == 0 {
+		return 0, nil
+	}
+
+	return Add(list[0], list[1:]...)
+}
+
+// fieldValue returns the value at the provided dotted path in v. Each segment of the path
+// is a map key, a struct field or a list index. An empty path returns v itself.
+func fieldValue(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		val := reflect.ValueOf(v)
+		for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+			if val.IsNil() {
+				return nil, false
+			}
+			val = val.Elem()
+		}
+
+		switch val.Kind() {
+		case reflect.Map:
+			if val.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+
+			el := val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))
+			if !el.IsValid() {
+				return nil, false
+			}
+			v = el.Interface()
+		case reflect.Struct:
+			el := val.FieldByName(key)
+			if !el.IsValid() || !el.CanInterface() {
+				return nil, false
+			}
+			v = el.Interface()
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= val.Len() {
+				return nil, false
+			}
+			v = val.Index(i).Interface()
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// comparableNumbers converts a and b to floats if both are numbers, or if one is a number
+// and the other is a numeric string.
+func comparableNumbers(a, b interface{}) (float64, float64, bool) {
+	_, aStr := a.(string)
+	_, bStr := b.(string)
+	if aStr && bStr {
+		return 0, 0, false
+	}
+
+	af, err := toFloat(a)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	bf, err := toFloat(b)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return af, bf, true
+}
+
+func equal(a, b interface{}) bool {
+	if af, bf, ok := comparableNumbers(a, b); ok {
+		return af == bf
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders a and b, comparing numbers numerically, times chronologically and
+// anything else by its string representation. nil sorts before everything else. The
+// second return value reports whether a and b were of comparable types.
+func compare(a, b interface{}) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, true
+	case a == nil:
+		return -1, false
+	case b == nil:
+		return 1, false
+	}
+
+	if af, bf, ok := comparableNumbers(a, b); ok {
+		switch {
+		case af < bf:
+			return -1, true
+		case af > bf:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt), true
+		}
+	}
+
+	if ab, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ab == bb:
+				return 0, true
+			case !ab:
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+
+	_, aStr := a.(string)
+	_, bStr := b.(string)
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)), aStr && bStr
+}
@@ -0,0 +1,253 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testPosts() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"title": "b", "views": 10, "draft": false, "tags": []interface{}{"go"}, "author": map[string]interface{}{"name": "ann"}},
+		map[string]interface{}{"title": "a", "views": 2.5, "draft": true, "tags": []interface{}{"js", "go"}, "author": map[string]interface{}{"name": "bob"}},
+		map[string]interface{}{"title": "c", "views": "30", "tags": []interface{}{}},
+	}
+}
+
+func titles(t *testing.T, list []interface{}) []string {
+	t.Helper()
+
+	tbr := []string{}
+	for _, el := range list {
+		tbr = append(tbr, el.(map[string]interface{})["title"].(string))
+	}
+
+	return tbr
+}
+
+func TestWhere(t *testing.T) {
+	tests := []struct {
+		field string
+		args  []interface{}
+		want  []string
+	}{
+		{"draft", []interface{}{true}, []string{"a"}},
+		{"draft", []interface{}{"!=", true}, []string{"b", "c"}},
+		{"views", []interface{}{">", 5}, []string{"b", "c"}},
+		{"views", []interface{}{"<=", "10"}, []string{"b", "a"}},
+		{"views", []interface{}{"in", []int{10, 30}}, []string{"b", "c"}},
+		{"views", []interface{}{"not in", []int{10, 30}}, []string{"a"}},
+		{"tags", []interface{}{"contains", "go"}, []string{"b", "a"}},
+		{"title", []interface{}{"contains", "c"}, []string{"c"}},
+		{"author.name", []interface{}{"bob"}, []string{"a"}},
+		{"tags.0", []interface{}{"js"}, []string{"a"}},
+	}
+
+	for _, test := range tests {
+		got, err := Where(testPosts(), test.field, test.args...)
+		if err != nil {
+			t.Errorf("Where(%s, %v): %s", test.field, test.args, err)
+			continue
+		}
+
+		if g := titles(t, got); !reflect.DeepEqual(g, test.want) {
+			t.Errorf("Where(%s, %v) = %v, want %v", test.field, test.args, g, test.want)
+		}
+	}
+
+	if _, err := Where(testPosts(), "views", "~", 1); err == nil {
+		t.Errorf("Where with an unknown operator: expected an error")
+	}
+
+	if _, err := Where(testPosts(), "views"); err == nil {
+		t.Errorf("Where without a value: expected an error")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	tests := []struct {
+		field string
+		order []string
+		want  []string
+	}{
+		{"title", nil, []string{"a", "b", "c"}},
+		{"title", []string{"desc"}, []string{"c", "b", "a"}},
+		{"views", nil, []string{"a", "b", "c"}},
+		{"author.name", nil, []string{"c", "b", "a"}},
+	}
+
+	for _, test := range tests {
+		got, err := SortBy(testPosts(), test.field, test.order...)
+		if err != nil {
+			t.Errorf("SortBy(%s, %v): %s", test.field, test.order, err)
+			continue
+		}
+
+		if g := titles(t, got); !reflect.DeepEqual(g, test.want) {
+			t.Errorf("SortBy(%s, %v) = %v, want %v", test.field, test.order, g, test.want)
+		}
+	}
+
+	if _, err := SortBy(testPosts(), "title", "up"); err == nil {
+		t.Errorf("SortBy with an unknown order: expected an error")
+	}
+}
+
+func TestSortByMissingAndMixed(t *testing.T) {
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := []interface{}{
+		map[string]interface{}{"title": "none"},
+		map[string]interface{}{"title": "str", "v": "b"},
+		map[string]interface{}{"title": "time", "v": date},
+		map[string]interface{}{"title": "ten", "v": "10"},
+		map[string]interface{}{"title": "nil", "v": nil},
+		map[string]interface{}{"title": "two", "v": 2},
+		map[string]interface{}{"title": "bool", "v": true},
+		map[string]interface{}{"title": "str2", "v": "a"},
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"asc", []string{"none", "nil", "bool", "two", "ten", "time", "str2", "str"}},
+		{"desc", []string{"none", "nil", "str", "str2", "time", "ten", "two", "bool"}},
+	}
+
+	for _, test := range tests {
+		got, err := SortBy(list, "v", test.order)
+		if err != nil {
+			t.Errorf("SortBy(v, %s): %s", test.order, err)
+			continue
+		}
+
+		if g := titles(t, got); !reflect.DeepEqual(g, test.want) {
+			t.Errorf("SortBy(v, %s) = %v, want %v", test.order, g, test.want)
+		}
+	}
+}
+
+func TestSortCompareTotal(t *testing.T) {
+	values := []interface{}{
+		nil, false, true, 1, 2.5, "2", "10", "1a", "a", "B",
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), []int{1}, map[string]int{"a": 1},
+	}
+
+	sign := func(c int) int {
+		switch {
+		case c < 0:
+			return -1
+		case c > 0:
+			return 1
+		}
+		return 0
+	}
+
+	for _, a := range values[1:] {
+		if c := sortCompare(a, a); c != 0 {
+			t.Errorf("sortCompare(%v, %v) = %d, want 0", a, a, c)
+		}
+
+		for _, b := range values[1:] {
+			ab, ba := sign(sortCompare(a, b)), sign(sortCompare(b, a))
+			if ab != -ba {
+				t.Errorf("sortCompare(%v, %v) = %d but sortCompare(%v, %v) = %d", a, b, ab, b, a, ba)
+			}
+
+			for _, c := range values[1:] {
+				if ab < 0 && sortCompare(b, c) < 0 && sortCompare(a, c) >= 0 {
+					t.Errorf("sortCompare isn't transitive: %v < %v < %v, but not %v < %v", a, b, c, a, c)
+				}
+			}
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	got, err := GroupBy(testPosts(), "draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{"false": {"b"}, "true": {"a"}, "": {"c"}}
+	if len(got) != len(want) {
+		t.Errorf("GroupBy(draft) has %d groups, want %d", len(got), len(want))
+	}
+
+	for k, v := range want {
+		list, _ := got[k].([]interface{})
+		if g := titles(t, list); !reflect.DeepEqual(g, v) {
+			t.Errorf("GroupBy(draft)[%q] = %v, want %v", k, g, v)
+		}
+	}
+
+	counts, err := CountBy(testPosts(), "draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]int{"false": 1, "true": 1, "": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("CountBy(draft) = %v, want %v", counts, want)
+	}
+}
+
+func TestLimitOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(interface{}, interface{}) ([]interface{}, error)
+		n    interface{}
+		want []string
+	}{
+		{"limit", Limit, 2, []string{"b", "a"}},
+		{"limit past the end", Limit, 10, []string{"b", "a", "c"}},
+		{"limit negative", Limit, -1, []string{}},
+		{"offset", Offset, 2, []string{"c"}},
+		{"offset past the end", Offset, "5", []string{}},
+		{"offset negative", Offset, -1, []string{"b", "a", "c"}},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(testPosts(), test.n)
+		if err != nil {
+			t.Errorf("%s %v: %s", test.name, test.n, err)
+			continue
+		}
+
+		if g := titles(t, got); !reflect.DeepEqual(g, test.want) {
+			t.Errorf("%s %v = %v, want %v", test.name, test.n, g, test.want)
+		}
+	}
+}
+
+func TestPluckSum(t *testing.T) {
+	got, err := Pluck(testPosts(), "author.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []interface{}{"ann", "bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pluck(author.name) = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		list  interface{}
+		field []string
+		want  interface{}
+	}{
+		{[]int{1, 2, 3}, nil, 6},
+		{nil, nil, 0},
+		{testPosts(), []string{"views"}, 42.5},
+	}
+
+	for _, test := range tests {
+		got, err := Sum(test.list, test.field...)
+		if err != nil {
+			t.Errorf("Sum(%v, %v): %s", test.list, test.field, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("Sum(%v, %v) = %#v, want %#v", test.list, test.field, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package tmplfunc
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/html"
+)
+
+// CSPHashes collects the hashes of inline scripts and styles in the HTML documents of a
+// build, so they can be listed in a Content-Security-Policy. It's safe for concurrent
+// use.
+type CSPHashes struct {
+	mu   sync.Mutex
+	docs map[string]inlineHashes
+}
+
+type inlineHashes struct {
+	scripts []string
+	styles  []string
+}
+
+type CSPHasher interface {
+	CSPHashes() *CSPHashes
+}
+
+// NewCSPHashes returns an empty CSPHashes.
+func NewCSPHashes() *CSPHashes {
+	return &CSPHashes{
+		docs: map[string]inlineHashes{},
+	}
+}
+
+// Set records the hashes of the inline scripts and styles in the document named doc,
+// replacing any recorded for it before.
+func (c *CSPHashes) Set(doc string, scripts, styles []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.docs[doc] = inlineHashes{scripts: scripts, styles: styles}
+}
+
+// Scripts returns the hashes of every inline script recorded, sorted and without
+// duplicates.
+func (c *CSPHashes) Scripts() []string {
+	return c.collect(func(h inlineHashes) []string { return h.scripts })
+}
+
+// Styles returns the hashes of every inline style recorded, sorted and without
+// duplicates.
+func (c *CSPHashes) Styles() []string {
+	return c.collect(func(h inlineHashes) []string { return h.styles })
+}
+
+func (c *CSPHashes) collect(fn func(inlineHashes) []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := map[string]bool{}
+	tbr := []string{}
+	for _, h := range c.docs {
+		for _, v := range fn(h) {
+			if !seen[v] {
+				seen[v] = true
+				tbr = append(tbr, v)
+			}
+		}
+	}
+
+	sort.Strings(tbr)
+	return tbr
+}
+
+// CSPHash returns the Content-Security-Policy source for the provided inline script or
+// style, like 'sha256-...'.
+func CSPHash(s interface{}) string {
+	sum := sha256.Sum256([]byte(toString(s)))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// InlineHashes returns the CSP hashes of the contents of every inline script (one without
+// a src attribute) and style element in the provided HTML document.
+func InlineHashes(doc []byte) (scripts []string, styles []string) {
+	l := html.NewLexer(parse.NewInputBytes(doc))
+
+	tag, hasSrc := "", false
+	for {
+		tt, data := l.Next()
+		switch tt {
+		case html.ErrorToken:
+			return scripts, styles
+		case html.StartTagToken:
+			tag, hasSrc = strings.ToLower(string(l.Text())), false
+		case html.AttributeToken:
+			if tag == "script" && strings.EqualFold(string(l.Text()), "src") {
+				hasSrc = true
+			}
+		case html.EndTagToken:
+			tag = ""
+		case html.TextToken:
+			switch {
+			case tag == "script" && !hasSrc:
+				scripts = append(scripts, CSPHash(string(data)))
+			case tag == "style":
+				styles = append(styles, CSPHash(string(data)))
+			}
+		}
+	}
+}
+
+// CSPHashList returns a function which returns the CSP hashes of the inline scripts
+// ("script") or styles ("style") in every html block rendered so far. Blocks are
+// rendered in the order they're configured, so the result only covers the html blocks
+// listed before the block using it; in watch mode, it also keeps the hashes from each
+// html block's last render.
+func CSPHashList(c CSPHasher) func(string) ([]string, error) {
+	return func(kind string) ([]string, error) {
+		hashes := c.CSPHashes()
+		if hashes == nil {
+			return []string{}, nil
+		}
+
+		switch kind {
+		case "script", "scripts":
+			return hashes.Scripts(), nil
+		case "style", "styles":
+			return hashes.Styles(), nil
+		}
+
+		return nil, errors.Errorf("cspHashes: unknown kind %q (expected script or style)", kind)
+	}
+}
+
+// Integrity returns a function which returns the Subresource Integrity value, like
+// sha384-..., for the asset at the provided path in the public directory. It also marks
+// the asset as updateable.
+func Integrity(a AssetRefer) func(string) (string, error) {
+	return func(p string) (string, error) {
+		file := path.Clean(p)
+		if dir := a.AssetOptions().Dir; dir != "" {
+			var err error
+			if file, err = publicFile(dir, p); err != nil {
+				return "", errors.Wrap(err, "integrity")
+			}
+		}
+
+		by, err := fs.ReadFile(a.FS(), file)
+		if err != nil {
+			return "", errors.Wrapf(err, "integrity: read (path: %s)", p)
+		}
+
+		a.Ref(file)
+
+		sum := sha512.Sum384(by)
+		return "sha384-" + base64.StdEncoding.EncodeToString(sum[:]), nil
+	}
+}
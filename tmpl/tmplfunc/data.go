@@ -0,0 +1,106 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML marshals the provided input as YAML, indented by two spaces, without a
+// trailing newline.
+func ToYAML(v interface{}) (string, error) {
+	buf := bytes.Buffer{}
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", errors.Wrap(err, "yaml: marshal")
+	}
+
+	if err := enc.Close(); err != nil {
+		return "", errors.Wrap(err, "yaml: marshal")
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// ToTOML marshals the provided map as a TOML document, without a trailing newline.
+func ToTOML(v interface{}) (string, error) {
+	by, err := toml.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "toml: marshal")
+	}
+
+	return strings.TrimSuffix(string(by), "\n"), nil
+}
+
+// ToCSV writes the provided rows as CSV, without a trailing newline. Each row is either
+// a list of fields, or a map; if the rows are maps, a header row of their keys, sorted,
+// is written first.
+func ToCSV(v interface{}) (string, error) {
+	rows, err := toSlice(v)
+	if err != nil {
+		return "", err
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+
+	var header []string
+	if len(rows) > 0 {
+		if _, ok := rows[0].(map[string]interface{}); ok {
+			header = Keys(dictsOf(rows)...)
+			records = append(records, header)
+		}
+	}
+
+	for _, row := range rows {
+		var fields []interface{}
+		if header != nil {
+			m, ok := row.(map[string]interface{})
+			if !ok {
+				return "", errors.Errorf("expected every row to be a map, got %T", row)
+			}
+
+			for _, key := range header {
+				fields = append(fields, m[key])
+			}
+		} else {
+			fields, err = toSlice(row)
+			if err != nil {
+				return "", errors.Wrap(err, "row")
+			}
+		}
+
+		record := make([]string, len(fields))
+		for i, field := range fields {
+			if field != nil {
+				record[i] = toString(field)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	buf := bytes.Buffer{}
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		return "", errors.Wrap(err, "csv: write")
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// dictsOf returns the maps in rows.
+func dictsOf(rows []interface{}) []map[string]interface{} {
+	tbr := []map[string]interface{}{}
+	for _, row := range rows {
+		if m, ok := row.(map[string]interface{}); ok {
+			tbr = append(tbr, m)
+		}
+	}
+
+	return tbr
+}
@@ -0,0 +1,62 @@
+package tmplfunc
+
+import "testing"
+
+func TestToYAML(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{map[string]interface{}{"b": []int{1, 2}, "a": "x"}, "a: x\nb:\n  - 1\n  - 2"},
+		{[]string{"a"}, "- a"},
+		{"yes", `"yes"`},
+	}
+
+	for _, test := range tests {
+		got, err := ToYAML(test.in)
+		if err != nil || got != test.want {
+			t.Errorf("toYAML(%v) = %q, %v, want %q", test.in, got, err, test.want)
+		}
+	}
+}
+
+func TestToTOML(t *testing.T) {
+	got, err := ToTOML(map[string]interface{}{"title": "x", "owner": map[string]interface{}{"name": "y"}})
+	if want := "title = 'x'\n\n[owner]\nname = 'y'"; err != nil || got != want {
+		t.Errorf("toTOML = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestToCSV(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{[]interface{}{[]interface{}{"a", 1}, []string{"b,c", `d"`}}, "a,1\n\"b,c\",\"d\"\"\""},
+		{
+			[]interface{}{
+				map[string]interface{}{"name": "a", "n": 1},
+				map[string]interface{}{"name": "b", "extra": true},
+			},
+			"extra,n,name\n,1,a\ntrue,,b",
+		},
+		{[]interface{}{}, ""},
+	}
+
+	for _, test := range tests {
+		got, err := ToCSV(test.in)
+		if err != nil || got != test.want {
+			t.Errorf("toCSV(%v) = %q, %v, want %q", test.in, got, err, test.want)
+		}
+	}
+
+	for _, in := range []interface{}{
+		"a,b",
+		[]interface{}{map[string]interface{}{"a": 1}, []string{"b"}},
+		[]interface{}{"a"},
+	} {
+		if _, err := ToCSV(in); err == nil {
+			t.Errorf("toCSV(%v): expected an error", in)
+		}
+	}
+}
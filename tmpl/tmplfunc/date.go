@@ -0,0 +1,545 @@
+package tmplfunc
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateLayouts are the layouts ParseDate tries, in order.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"01/02/2006 15:04:05",
+	"01/02/2006",
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.UnixDate,
+	time.RubyDate,
+	time.ANSIC,
+	"January 2, 2006 15:04",
+	"January 2, 2006",
+	"Jan 2, 2006 15:04",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"20060102",
+}
+
+// ParseTimeLayout parses s using the provided Go layout.
+//
+// See: https://golang.org/pkg/time#Parse
+func ParseTimeLayout(layout, s string) (time.Time, error) {
+	return time.Parse(layout, s)
+}
+
+// ParseDate parses s, detecting its format from a list of common layouts, including
+// RFC 3339, RFC 1123, ISO 8601 dates and US-style dates.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.Errorf("parse date: unrecognized format: %q", s)
+}
+
+// ToTime converts a time.Time, a date string (see ParseDate) or a Unix timestamp into a
+// time.Time.
+func ToTime(v interface{}) (time.Time, error) {
+	return toTime(v)
+}
+
+// toTime converts a time.Time, a date string (see ParseDate) or a Unix timestamp into a
+// time.Time.
+func toTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		return *v, nil
+	case string:
+		return ParseDate(v)
+	}
+
+	n, err := toNumber(v)
+	if err != nil {
+		return time.Time{}, errors.Errorf("expected a time, got %T", v)
+	}
+
+	return unixTime(n), nil
+}
+
+func unixTime(n number) time.Time {
+	if n.isFloat {
+		sec, frac := math.Modf(n.f)
+		return time.Unix(int64(sec), int64(frac*1e9))
+	}
+
+	return time.Unix(n.i, 0)
+}
+
+// Unix returns the provided time as a Unix timestamp, in seconds.
+func Unix(t interface{}) (int64, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return 0, err
+	}
+
+	return tt.Unix(), nil
+}
+
+// FromUnix returns the local time corresponding to the provided Unix timestamp, in
+// seconds.
+func FromUnix(v interface{}) (time.Time, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return unixTime(n), nil
+}
+
+var offsetPattern = regexp.MustCompile(`([+-]?\d+(?:\.\d+)?)\s*(y|mo|w|d|h|ms|us|µs|ns|m|s)`)
+
+// DateAdd adds an offset to the provided time. The offset is a sequence of numbers with
+// units, like "1y2mo", "-3d" or "1h30m". Years (y), months (mo), weeks (w) and days (d)
+// are added on the calendar, so adding 1mo to January 31 gives March 2 or 3, and must be
+// whole numbers; hours (h), minutes (m), seconds (s), ms, us and ns are added as
+// durations.
+func DateAdd(offset string, t interface{}) (time.Time, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	s := strings.ReplaceAll(offset, " ", "")
+	sign := 1.0
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+
+	matches := offsetPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 || matches[0][0] != 0 || matches[len(matches)-1][1] != len(s) {
+		return time.Time{}, errors.Errorf("dateAdd: invalid offset %q", offset)
+	}
+
+	var years, months, days int
+	var dur time.Duration
+	for i, m := range matches {
+		if i > 0 && m[0] != matches[i-1][1] {
+			return time.Time{}, errors.Errorf("dateAdd: invalid offset %q", offset)
+		}
+
+		n, _ := strconv.ParseFloat(s[m[2]:m[3]], 64)
+		n *= sign
+
+		unit := s[m[4]:m[5]]
+		switch unit {
+		case "y", "mo", "w", "d":
+			if n != math.Trunc(n) {
+				return time.Time{}, errors.Errorf("dateAdd: %s must be a whole number in offset %q", unit, offset)
+			}
+		}
+
+		switch unit {
+		case "y":
+			years += int(n)
+		case "mo":
+			months += int(n)
+		case "w":
+			days += int(n) * 7
+		case "d":
+			days += int(n)
+		default:
+			d, err := time.ParseDuration(strconv.FormatFloat(n, 'f', -1, 64) + unit)
+			if err != nil {
+				return time.Time{}, errors.Wrapf(err, "dateAdd: invalid offset %q", offset)
+			}
+			dur += d
+		}
+	}
+
+	return tt.AddDate(years, months, days).Add(dur), nil
+}
+
+// DateDiff returns the number of whole units between from and to, which is negative if
+// to is before from. The unit is one of years, months, weeks, days, hours, minutes or
+// seconds. Years, months, weeks and days are counted on the calendar in from's location,
+// so a day across a daylight saving time change is still one day.
+func DateDiff(unit string, from, to interface{}) (int, error) {
+	a, err := toTime(from)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := toTime(to)
+	if err != nil {
+		return 0, err
+	}
+
+	switch strings.TrimSuffix(strings.ToLower(unit), "s") {
+	case "year":
+		return monthsBetween(a, b) / 12, nil
+	case "month":
+		return monthsBetween(a, b), nil
+	case "week":
+		return daysBetween(a, b) / 7, nil
+	case "day":
+		return daysBetween(a, b), nil
+	case "hour":
+		return int(b.Sub(a) / time.Hour), nil
+	case "minute":
+		return int(b.Sub(a) / time.Minute), nil
+	case "second":
+		return int(b.Sub(a) / time.Second), nil
+	default:
+		return 0, errors.Errorf("dateDiff: unknown unit %q", unit)
+	}
+}
+
+// monthsBetween returns the number of whole calendar months between a and b.
+func monthsBetween(a, b time.Time) int {
+	if b.Before(a) {
+		return -monthsBetween(b, a)
+	}
+
+	b = b.In(a.Location())
+	n := (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	if a.AddDate(0, n, 0).After(b) {
+		n--
+	}
+
+	return n
+}
+
+// daysBetween returns the number of whole calendar days between a and b, in a's
+// location.
+func daysBetween(a, b time.Time) int {
+	if b.Before(a) {
+		return -daysBetween(b, a)
+	}
+
+	b = b.In(a.Location())
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	n := int(db.Sub(da) / (24 * time.Hour))
+	if a.AddDate(0, 0, n).After(b) {
+		n--
+	}
+
+	return n
+}
+
+// toDuration converts a time.Duration, a duration string like "1h30m" or a number of
+// seconds into a time.Duration.
+func toDuration(v interface{}) (time.Duration, error) {
+	switch v := v.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, nil
+		}
+	}
+
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, errors.Errorf("expected a duration, got %T", v)
+	}
+
+	return time.Duration(f * float64(time.Second)), nil
+}
+
+var durationUnits = []struct {
+	name string
+	d    time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// HumanizeDuration describes the provided duration in words using its two largest
+// units, like "1 hour 30 minutes". The duration may be a time.Duration, a string like
+// "90m" or a number of seconds.
+func HumanizeDuration(v interface{}) (string, error) {
+	d, err := toDuration(v)
+	if err != nil {
+		return "", err
+	}
+
+	return humanize(d, 2), nil
+}
+
+func humanize(d time.Duration, parts int) string {
+	if d < 0 {
+		d = -d
+	}
+
+	var words []string
+	for _, u := range durationUnits {
+		if len(words) == parts {
+			break
+		}
+
+		if n := d / u.d; n > 0 {
+			words = append(words, plural(int(n), u.name))
+			d -= n * u.d
+		} else if len(words) > 0 {
+			break
+		}
+	}
+
+	if len(words) == 0 {
+		return plural(0, "second")
+	}
+
+	return strings.Join(words, " ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// TimeAgo returns a function which describes the provided time relative to now, like
+// "3 days ago" or "in 2 hours".
+func TimeAgo(now time.Time) func(interface{}) (string, error) {
+	return func(t interface{}) (string, error) {
+		tt, err := toTime(t)
+		if err != nil {
+			return "", err
+		}
+
+		d := now.Sub(tt)
+		switch {
+		case d > -time.Minute && d < time.Minute:
+			return "just now", nil
+		case d > 0:
+			return humanize(d, 1) + " ago", nil
+		default:
+			return "in " + humanize(d, 1), nil
+		}
+	}
+}
+
+// StartOf returns the beginning of the minute, hour, day, week, month, quarter or year
+// containing the provided time. Weeks start on Monday.
+func StartOf(unit string, t interface{}) (time.Time, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	y, m, d := tt.Date()
+	loc := tt.Location()
+
+	switch strings.ToLower(unit) {
+	case "minute":
+		return tt.Truncate(time.Minute), nil
+	case "hour":
+		return time.Date(y, m, d, tt.Hour(), 0, 0, 0, loc), nil
+	case "day":
+		return time.Date(y, m, d, 0, 0, 0, 0, loc), nil
+	case "week":
+		return time.Date(y, m, d-(int(tt.Weekday())+6)%7, 0, 0, 0, 0, loc), nil
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc), nil
+	case "quarter":
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, loc), nil
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc), nil
+	default:
+		return time.Time{}, errors.Errorf("startOf: unknown unit %q", unit)
+	}
+}
+
+// EndOf returns the last instant of the minute, hour, day, week, month, quarter or year
+// containing the provided time.
+func EndOf(unit string, t interface{}) (time.Time, error) {
+	start, err := StartOf(unit, t)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "endOf")
+	}
+
+	var next time.Time
+	switch strings.ToLower(unit) {
+	case "minute":
+		next = start.Add(time.Minute)
+	case "hour":
+		next = start.Add(time.Hour)
+	case "day":
+		next = start.AddDate(0, 0, 1)
+	case "week":
+		next = start.AddDate(0, 0, 7)
+	case "month":
+		next = start.AddDate(0, 1, 0)
+	case "quarter":
+		next = start.AddDate(0, 3, 0)
+	case "year":
+		next = start.AddDate(1, 0, 0)
+	}
+
+	return next.Add(-time.Nanosecond), nil
+}
+
+// ISOWeek returns the ISO 8601 week number of the provided time.
+func ISOWeek(t interface{}) (int, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return 0, err
+	}
+
+	_, week := tt.ISOWeek()
+	return week, nil
+}
+
+// Strftime returns a function which formats the provided time using C strftime-style
+// directives, like "%Y-%m-%d". Month and weekday names are in the template's locale.
+func Strftime(l Localizer) func(string, interface{}) (string, error) {
+	return func(format string, t interface{}) (string, error) {
+		tt, err := toTime(t)
+		if err != nil {
+			return "", err
+		}
+
+		return strftime(format, tt, namesFor(l.Locale()))
+	}
+}
+
+// strftime formats t with the strftime directives in format. It returns an error for
+// directives it doesn't support.
+func strftime(format string, t time.Time, n localeNames) (string, error) {
+	b := strings.Builder{}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		if i == len(format)-1 {
+			return "", errors.Errorf("strftime: incomplete directive at the end of %q", format)
+		}
+
+		i++
+		switch format[i] {
+		case 'a':
+			b.WriteString(n.shortDays[t.Weekday()])
+		case 'A':
+			b.WriteString(n.days[t.Weekday()])
+		case 'b', 'h':
+			b.WriteString(n.shortMonths[t.Month()-1])
+		case 'B':
+			b.WriteString(n.months[t.Month()-1])
+		case 'c':
+			s, _ := strftime("%a %b %e %H:%M:%S %Y", t, n)
+			b.WriteString(s)
+		case 'C':
+			fmt.Fprintf(&b, "%02d", t.Year()/100)
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'D':
+			b.WriteString(t.Format("01/02/06"))
+		case 'e':
+			fmt.Fprintf(&b, "%2d", t.Day())
+		case 'F':
+			b.WriteString(t.Format("2006-01-02"))
+		case 'g':
+			y, _ := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", y%100)
+		case 'G':
+			y, _ := t.ISOWeek()
+			fmt.Fprintf(&b, "%d", y)
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'I':
+			fmt.Fprintf(&b, "%02d", (t.Hour()+11)%12+1)
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'k':
+			fmt.Fprintf(&b, "%2d", t.Hour())
+		case 'l':
+			fmt.Fprintf(&b, "%2d", (t.Hour()+11)%12+1)
+		case 'L':
+			fmt.Fprintf(&b, "%03d", t.Nanosecond()/int(time.Millisecond))
+		case 'm':
+			fmt.Fprintf(&b, "%02d", t.Month())
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'n':
+			b.WriteByte('\n')
+		case 'p':
+			b.WriteString(t.Format("PM"))
+		case 'P':
+			b.WriteString(t.Format("pm"))
+		case 'R':
+			b.WriteString(t.Format("15:04"))
+		case 's':
+			fmt.Fprintf(&b, "%d", t.Unix())
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case 't':
+			b.WriteByte('\t')
+		case 'T':
+			b.WriteString(t.Format("15:04:05"))
+		case 'u':
+			fmt.Fprintf(&b, "%d", (int(t.Weekday())+6)%7+1)
+		case 'U':
+			// Weeks start on Sunday; days before the first Sunday are in week 0.
+			fmt.Fprintf(&b, "%02d", (t.YearDay()+6-int(t.Weekday()))/7)
+		case 'V':
+			_, w := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", w)
+		case 'w':
+			fmt.Fprintf(&b, "%d", t.Weekday())
+		case 'W':
+			// Weeks start on Monday; days before the first Monday are in week 0.
+			fmt.Fprintf(&b, "%02d", (t.YearDay()+6-(int(t.Weekday())+6)%7)/7)
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'Y':
+			fmt.Fprintf(&b, "%d", t.Year())
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case 'Z':
+			b.WriteString(t.Format("MST"))
+		case '%':
+			b.WriteByte('%')
+		default:
+			return "", errors.Errorf("strftime: unsupported directive %%%c", format[i])
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,271 @@
+package tmplfunc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []string{
+		"2024-03-05",
+		"2024/03/05",
+		"03/05/2024",
+		"March 5, 2024",
+		"Mar 5, 2024",
+		"5 March 2024",
+		"20240305",
+		"  2024-03-05T00:00:00Z ",
+	}
+
+	for _, s := range tests {
+		got, err := ParseDate(s)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %s", s, err)
+			continue
+		}
+
+		if !got.Equal(want) {
+			t.Errorf("ParseDate(%q) = %s, want %s", s, got, want)
+		}
+	}
+
+	if _, err := ParseDate("next tuesday"); err == nil {
+		t.Errorf("ParseDate(\"next tuesday\"): expected an error")
+	}
+}
+
+func TestDateAdd(t *testing.T) {
+	tests := []struct {
+		offset string
+		t      string
+		want   string
+	}{
+		{"1d", "2024-01-15T10:00:00Z", "2024-01-16T10:00:00Z"},
+		{"-3d", "2024-01-15T10:00:00Z", "2024-01-12T10:00:00Z"},
+		{"1w", "2024-01-15T10:00:00Z", "2024-01-22T10:00:00Z"},
+		{"1y2mo", "2024-01-15T10:00:00Z", "2025-03-15T10:00:00Z"},
+		{"1mo", "2024-01-31T10:00:00Z", "2024-03-02T10:00:00Z"},
+		{"1h30m", "2024-01-15T10:00:00Z", "2024-01-15T11:30:00Z"},
+		{"1.5h", "2024-01-15T10:00:00Z", "2024-01-15T11:30:00Z"},
+		{"-1d 12h", "2024-01-15T10:00:00Z", "2024-01-13T22:00:00Z"},
+	}
+
+	for _, test := range tests {
+		got, err := DateAdd(test.offset, test.t)
+		if err != nil {
+			t.Errorf("DateAdd(%q, %s): %s", test.offset, test.t, err)
+			continue
+		}
+
+		if s := got.Format(time.RFC3339); s != test.want {
+			t.Errorf("DateAdd(%q, %s) = %s, want %s", test.offset, test.t, s, test.want)
+		}
+	}
+
+	for _, offset := range []string{"", "tomorrow", "1x", "1d tomorrow", "1.5d", "0.5w", "1.5mo", "2.5y"} {
+		if _, err := DateAdd(offset, "2024-01-15"); err == nil {
+			t.Errorf("DateAdd(%q): expected an error", offset)
+		}
+	}
+}
+
+func TestDateDiff(t *testing.T) {
+	tests := []struct {
+		unit     string
+		from, to interface{}
+		want     int
+	}{
+		{"days", "2024-01-01", "2024-01-15", 14},
+		{"days", "2024-01-15", "2024-01-01", -14},
+		{"days", "2024-01-01T12:00:00Z", "2024-01-02T11:59:59Z", 0},
+		{"weeks", "2024-01-01", "2024-01-15", 2},
+		{"weeks", "2024-01-01", "2024-01-14", 1},
+		{"months", "2024-01-31", "2024-02-29", 0},
+		{"months", "2024-01-31", "2024-03-31", 2},
+		{"years", "2020-02-29", "2021-02-28", 0},
+		{"years", "2020-02-29", "2021-03-01", 1},
+		{"hours", "2024-01-01T10:00:00Z", "2024-01-01T08:30:00Z", -1},
+		{"minute", "2024-01-01T10:00:00Z", "2024-01-01T10:02:59Z", 2},
+		{"seconds", 0, 90, 90},
+	}
+
+	for _, test := range tests {
+		got, err := DateDiff(test.unit, test.from, test.to)
+		if err != nil {
+			t.Errorf("DateDiff(%s, %v, %v): %s", test.unit, test.from, test.to, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("DateDiff(%s, %v, %v) = %d, want %d", test.unit, test.from, test.to, got, test.want)
+		}
+	}
+
+	if _, err := DateDiff("fortnights", "2024-01-01", "2024-01-02"); err == nil {
+		t.Errorf("DateDiff with an unknown unit: expected an error")
+	}
+}
+
+func TestDateDiffDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("load location: %s", err)
+	}
+
+	// Clocks went forward on March 10, 2024, so these are 47 hours, but 2 calendar days,
+	// apart.
+	from := time.Date(2024, time.March, 9, 12, 0, 0, 0, loc)
+	to := time.Date(2024, time.March, 11, 12, 0, 0, 0, loc)
+
+	if got, err := DateDiff("days", from, to); err != nil || got != 2 {
+		t.Errorf("DateDiff(days) across DST = %d, %v, want 2", got, err)
+	}
+
+	if got, err := DateDiff("days", to, from); err != nil || got != -2 {
+		t.Errorf("DateDiff(days) back across DST = %d, %v, want -2", got, err)
+	}
+
+	from = time.Date(2024, time.March, 4, 12, 0, 0, 0, loc)
+	if got, err := DateDiff("weeks", from, to); err != nil || got != 1 {
+		t.Errorf("DateDiff(weeks) across DST = %d, %v, want 1", got, err)
+	}
+}
+
+func TestStartEndOf(t *testing.T) {
+	tm := time.Date(2024, time.May, 23, 14, 35, 10, 500, time.UTC)
+
+	tests := []struct {
+		unit       string
+		start, end string
+	}{
+		{"minute", "2024-05-23T14:35:00Z", "2024-05-23T14:35:59.999999999Z"},
+		{"hour", "2024-05-23T14:00:00Z", "2024-05-23T14:59:59.999999999Z"},
+		{"day", "2024-05-23T00:00:00Z", "2024-05-23T23:59:59.999999999Z"},
+		{"week", "2024-05-20T00:00:00Z", "2024-05-26T23:59:59.999999999Z"},
+		{"month", "2024-05-01T00:00:00Z", "2024-05-31T23:59:59.999999999Z"},
+		{"quarter", "2024-04-01T00:00:00Z", "2024-06-30T23:59:59.999999999Z"},
+		{"year", "2024-01-01T00:00:00Z", "2024-12-31T23:59:59.999999999Z"},
+	}
+
+	for _, test := range tests {
+		start, err := StartOf(test.unit, tm)
+		if err != nil {
+			t.Errorf("StartOf(%s): %s", test.unit, err)
+			continue
+		}
+
+		end, err := EndOf(test.unit, tm)
+		if err != nil {
+			t.Errorf("EndOf(%s): %s", test.unit, err)
+			continue
+		}
+
+		if s := start.Format(time.RFC3339Nano); s != test.start {
+			t.Errorf("StartOf(%s) = %s, want %s", test.unit, s, test.start)
+		}
+
+		if s := end.Format(time.RFC3339Nano); s != test.end {
+			t.Errorf("EndOf(%s) = %s, want %s", test.unit, s, test.end)
+		}
+	}
+
+	if _, err := StartOf("decade", tm); err == nil {
+		t.Errorf("StartOf(decade): expected an error")
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{0, "0 seconds"},
+		{1, "1 second"},
+		{5400, "1 hour 30 minutes"},
+		{3601, "1 hour"},
+		{"90s", "1 minute 30 seconds"},
+		{"26h", "1 day 2 hours"},
+		{-90 * time.Second, "1 minute 30 seconds"},
+	}
+
+	for _, test := range tests {
+		got, err := HumanizeDuration(test.v)
+		if err != nil {
+			t.Errorf("HumanizeDuration(%v): %s", test.v, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("HumanizeDuration(%v) = %q, want %q", test.v, got, test.want)
+		}
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2024, time.May, 23, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-3 * 24 * time.Hour), "3 days ago"},
+		{now.Add(-61 * time.Minute), "1 hour ago"},
+		{now.Add(2 * time.Hour), "in 2 hours"},
+	}
+
+	for _, test := range tests {
+		got, err := TimeAgo(now)(test.t)
+		if err != nil {
+			t.Errorf("TimeAgo(%s): %s", test.t, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("TimeAgo(%s) = %q, want %q", test.t, got, test.want)
+		}
+	}
+}
+
+func TestStrftime(t *testing.T) {
+	tm := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+
+	tests := []struct {
+		locale, format string
+		t              time.Time
+		want           string
+	}{
+		{"en", "%Y-%m-%d %H:%M:%S", tm, "2024-03-05 14:07:09"},
+		{"en", "%a %A %b %B", tm, "Tue Tuesday Mar March"},
+		{"de", "%a %A %b %B", tm, "Di. Dienstag März März"},
+		{"en", "%I|%l|%p|%P|%j|%e|%k", tm, "02| 2|PM|pm|065| 5|14"},
+		{"en", "%F %T %D %R", tm, "2024-03-05 14:07:09 03/05/24 14:07"},
+		{"en", "%u %w %C %y %z %Z %%", tm, "2 2 20 24 +0000 UTC %"},
+		{"en", "%c", tm, "Tue Mar  5 14:07:09 2024"},
+
+		// January 1, 2023 was a Sunday in the last ISO week of 2022.
+		{"en", "%U %W %V %G %g", time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), "01 00 52 2022 22"},
+		{"en", "%U %W %V %G %g", time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC), "01 01 01 2024 24"},
+		{"en", "%U %W", time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC), "52 53"},
+	}
+
+	for _, test := range tests {
+		got, err := Strftime(testLocalizer(test.locale))(test.format, test.t)
+		if err != nil {
+			t.Errorf("strftime %q (%s): %s", test.format, test.locale, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("strftime %q (%s) = %q, want %q", test.format, test.locale, got, test.want)
+		}
+	}
+
+	for _, format := range []string{"%Q", "%Y-%", "%E"} {
+		if _, err := Strftime(testLocalizer("en"))(format, tm); err == nil {
+			t.Errorf("strftime %q: expected an error", format)
+		}
+	}
+}
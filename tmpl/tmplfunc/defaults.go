@@ -0,0 +1,50 @@
+package tmplfunc
+
+import "reflect"
+
+// Default returns the given value, or def if the given value is empty or missing.
+func Default(def interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || Empty(given[0]) {
+		return def
+	}
+
+	return given[0]
+}
+
+// Empty reports whether v is nil or its type's zero value, or an empty string, slice
+// or map.
+func Empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	default:
+		return val.IsZero()
+	}
+}
+
+// Coalesce returns the first non-empty argument, or nil if they're all empty.
+func Coalesce(v ...interface{}) interface{} {
+	for _, el := range v {
+		if !Empty(el) {
+			return el
+		}
+	}
+
+	return nil
+}
+
+// Ternary returns vt if cond is true, and vf otherwise.
+func Ternary(vt, vf interface{}, cond bool) interface{} {
+	if cond {
+		return vt
+	}
+
+	return vf
+}
@@ -0,0 +1,124 @@
+package tmplfunc
+
+import "sort"
+
+// Dict returns a map built from the provided key/value pairs. Keys are converted to
+// strings; if a key has no value, its value is an empty string.
+func Dict(v ...interface{}) map[string]interface{} {
+	tbr := make(map[string]interface{}, len(v)/2)
+	for i := 0; i < len(v); i += 2 {
+		key := toString(v[i])
+		if i+1 < len(v) {
+			tbr[key] = v[i+1]
+		} else {
+			tbr[key] = ""
+		}
+	}
+
+	return tbr
+}
+
+// Get returns the value at key in the provided map, or an empty string if it's not set.
+func Get(d map[string]interface{}, key string) interface{} {
+	if v, ok := d[key]; ok {
+		return v
+	}
+
+	return ""
+}
+
+// Set sets key to value in the provided map and returns the map.
+func Set(d map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	d[key] = value
+	return d
+}
+
+// Unset removes key from the provided map and returns the map.
+func Unset(d map[string]interface{}, key string) map[string]interface{} {
+	delete(d, key)
+	return d
+}
+
+// HasKey reports whether key is set in the provided map.
+func HasKey(d map[string]interface{}, key string) bool {
+	_, ok := d[key]
+	return ok
+}
+
+// Keys returns the sorted, unique keys of the provided maps.
+func Keys(dicts ...map[string]interface{}) []string {
+	seen := map[string]struct{}{}
+	tbr := []string{}
+	for _, d := range dicts {
+		for k := range d {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				tbr = append(tbr, k)
+			}
+		}
+	}
+
+	sort.Strings(tbr)
+	return tbr
+}
+
+// Values returns the values of the provided map, ordered by key.
+func Values(d map[string]interface{}) []interface{} {
+	tbr := make([]interface{}, 0, len(d))
+	for _, k := range Keys(d) {
+		tbr = append(tbr, d[k])
+	}
+
+	return tbr
+}
+
+// Merge deeply merges each of the source maps into dst and returns dst. Keys already set
+// in dst take precedence; nested maps are merged recursively.
+func Merge(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+	for _, src := range srcs {
+		for k, sv := range src {
+			dv, ok := dst[k]
+			if !ok {
+				dst[k] = sv
+				continue
+			}
+
+			dm, dok := dv.(map[string]interface{})
+			sm, sok := sv.(map[string]interface{})
+			if dok && sok {
+				dst[k] = Merge(dm, sm)
+			}
+		}
+	}
+
+	return dst
+}
+
+// Pick returns a new map with only the provided keys.
+func Pick(d map[string]interface{}, keys ...string) map[string]interface{} {
+	tbr := map[string]interface{}{}
+	for _, k := range keys {
+		if v, ok := d[k]; ok {
+			tbr[k] = v
+		}
+	}
+
+	return tbr
+}
+
+// Omit returns a new map without the provided keys.
+func Omit(d map[string]interface{}, keys ...string) map[string]interface{} {
+	omit := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		omit[k] = struct{}{}
+	}
+
+	tbr := map[string]interface{}{}
+	for k, v := range d {
+		if _, ok := omit[k]; !ok {
+			tbr[k] = v
+		}
+	}
+
+	return tbr
+}
@@ -0,0 +1,57 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDict(t *testing.T) {
+	tests := []struct {
+		in   []interface{}
+		want map[string]interface{}
+	}{
+		{nil, map[string]interface{}{}},
+		{[]interface{}{"a", 1, "b", "x"}, map[string]interface{}{"a": 1, "b": "x"}},
+		{[]interface{}{1, true, "odd"}, map[string]interface{}{"1": true, "odd": ""}},
+	}
+
+	for _, test := range tests {
+		if got := Dict(test.in...); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Dict(%v) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestDictFuncs(t *testing.T) {
+	d := func() map[string]interface{} {
+		return map[string]interface{}{"a": 1, "b": 2, "c": map[string]interface{}{"x": 1}}
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"get", Get(d(), "a"), 1},
+		{"get missing", Get(d(), "z"), ""},
+		{"set", Set(map[string]interface{}{}, "a", 1), map[string]interface{}{"a": 1}},
+		{"unset", Unset(map[string]interface{}{"a": 1, "b": 2}, "a"), map[string]interface{}{"b": 2}},
+		{"hasKey", HasKey(d(), "b"), true},
+		{"hasKey missing", HasKey(d(), "z"), false},
+		{"keys", Keys(d(), map[string]interface{}{"a": 0, "d": 0}), []string{"a", "b", "c", "d"}},
+		{"values", Values(map[string]interface{}{"b": 2, "a": 1}), []interface{}{1, 2}},
+		{"pick", Pick(d(), "a", "z"), map[string]interface{}{"a": 1}},
+		{"omit", Omit(d(), "c", "z"), map[string]interface{}{"a": 1, "b": 2}},
+		{
+			"merge",
+			Merge(d(), map[string]interface{}{"a": 9, "c": map[string]interface{}{"x": 9, "y": 2}, "d": 4}),
+			map[string]interface{}{"a": 1, "b": 2, "c": map[string]interface{}{"x": 1, "y": 2}, "d": 4},
+		},
+	}
+
+	for _, test := range tests {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("%s = %#v, want %#v", test.name, test.got, test.want)
+		}
+	}
+}
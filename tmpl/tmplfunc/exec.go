@@ -0,0 +1,123 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultCommandTimeout is how long a Command may run if it doesn't specify a timeout.
+const DefaultCommandTimeout = 10 * time.Second
+
+// Command describes a template function implemented by an external command.
+type Command struct {
+	// Exec is the command and its arguments.
+	Exec []string
+
+	// Dir is the directory the command runs in. Relative paths in the command's deps
+	// are resolved against it.
+	Dir string
+
+	// Timeout is how long the command may run before it's killed.
+	Timeout time.Duration
+}
+
+type commandResult struct {
+	Result interface{} `json:"result"`
+	Deps   []string    `json:"deps"`
+}
+
+// Exec returns a function which runs the provided command, writing the function's
+// arguments to its stdin as a JSON array and decoding its stdout as a JSON object
+// of the form:
+//
+//	{"result": ..., "deps": ["path", ...]}
+//
+// The result is returned to the template, and each dep is marked as updateable.
+// Results are cached by arguments, so the command runs once for each distinct call.
+func Exec(r Refer, c Command) func(...interface{}) (interface{}, error) {
+	var mu sync.Mutex
+	cache := map[string]commandResult{}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+
+	return func(args ...interface{}) (interface{}, error) {
+		if len(c.Exec) == 0 {
+			return nil, errors.New("exec: no command specified")
+		}
+
+		if args == nil {
+			args = []interface{}{}
+		}
+
+		in, err := json.Marshal(args)
+		if err != nil {
+			return nil, errors.Wrap(err, "json: marshal args")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		res, ok := cache[string(in)]
+		if !ok {
+			res, err = runCommand(c, timeout, in)
+			if err != nil {
+				return nil, errors.Wrapf(err, "exec (%s)", strings.Join(c.Exec, " "))
+			}
+
+			cache[string(in)] = res
+		}
+
+		for _, dep := range res.Deps {
+			if c.Dir != "" && !filepath.IsAbs(dep) {
+				dep = filepath.Join(c.Dir, dep)
+			}
+
+			r.Ref(dep)
+		}
+
+		return res.Result, nil
+	}
+}
+
+func runCommand(c Command, timeout time.Duration, in []byte) (commandResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, c.Exec[0], c.Exec[1:]...)
+	cmd.Dir = c.Dir
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return commandResult{}, errors.Errorf("timed out after %s", timeout)
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return commandResult{}, errors.Wrapf(err, "run (stderr: %s)", msg)
+		}
+
+		return commandResult{}, errors.Wrap(err, "run")
+	}
+
+	var res commandResult
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil {
+		return commandResult{}, errors.Wrap(err, "json: decode output")
+	}
+
+	return res, nil
+}
@@ -1,63 +1,85 @@
 package tmplfunc
 
 import (
-	"bytes"
 	"html/template"
-	"io"
+	"io/fs"
 	"log"
-	"os"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 )
 
-// Asset returns the path provided. In the future, Asset may gain
-// the ability to clean or validate the path.
-func Asset(path string) string {
-	return path
+// OSFS is implemented by filesystems which open operating system paths, either absolute
+// or relative to the working directory, rather than slash-separated paths relative to
+// their root like other fs.FS implementations.
+type OSFS interface {
+	fs.FS
+	OSPaths()
 }
 
-// File reads the file at the provided path and returns its contents as a string.
-func File(path string) (string, error) {
-	fp, err := os.Open(path)
-	if err != nil {
-		return "", errors.Wrap(err, "os: open")
+// FSPath returns the path to open p at in fsys, with relative paths resolved against
+// base. If fsys is an OSFS, this is an operating system path, absolute if base is set;
+// otherwise it's a slash-separated path relative to the filesystem's root, and an
+// absolute base is ignored.
+func FSPath(fsys fs.FS, base, p string) string {
+	if _, ok := fsys.(OSFS); ok {
+		if !filepath.IsAbs(p) && base != "" {
+			if abs, err := filepath.Abs(base); err == nil {
+				base = abs
+			}
+
+			return filepath.Join(base, p)
+		}
+		return p
 	}
 
-	buf := bytes.Buffer{}
-	if _, err := io.Copy(&buf, fp); err != nil {
-		return "", errors.Wrap(err, "io: copy")
+	p = filepath.ToSlash(p)
+	if !path.IsAbs(p) && base != "" && !filepath.IsAbs(base) {
+		p = path.Join(filepath.ToSlash(base), p)
 	}
 
-	return buf.String(), nil
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
 }
 
-// Inline reads the file at the provided path and returns its contents as a string. It
-// also marks the file as updateable.
-func Inline(r Refer) func(string) (string, error) {
+// File returns a function which reads the file at the provided path, relative to the
+// template's base directory, and returns its contents as a string.
+func File(f Filesystem) func(string) (string, error) {
 	return func(path string) (string, error) {
-		fp, err := os.Open(path)
+		path = FSPath(f.FS(), f.BaseDir(), path)
+
+		by, err := fs.ReadFile(f.FS(), path)
 		if err != nil {
-			return "", errors.Wrap(err, "os: open")
+			return "", errors.Wrap(err, "fs: read file")
 		}
 
-		defer fp.Close()
+		return string(by), nil
+	}
+}
 
-		r.Ref(path)
+// Inline reads the file at the provided path, relative to the template's base directory,
+// and returns its contents as a string. It also marks the file as updateable.
+func Inline(r FilesystemRefer) func(string) (string, error) {
+	return func(path string) (string, error) {
+		path = FSPath(r.FS(), r.BaseDir(), path)
 
-		buf := bytes.Buffer{}
-		if _, err := io.Copy(&buf, fp); err != nil {
-			return "", errors.Wrap(err, "io: copy")
+		by, err := fs.ReadFile(r.FS(), path)
+		if err != nil {
+			return "", errors.Wrap(err, "fs: read file")
 		}
 
-		return buf.String(), nil
+		r.Ref(path)
+
+		return string(by), nil
 	}
 }
 
 // SVG reads the file at the provided path and returns its contents under the assumption
 // that it's an HTML-safe string. It also marks the SVG as updateable.
-func SVG(d Refer) func(string) (template.HTML, error) {
+func SVG(r FilesystemRefer) func(string) (template.HTML, error) {
 	return func(path string) (template.HTML, error) {
-		res, err := Inline(d)(path)
+		res, err := Inline(r)(path)
 		if err != nil {
 			return "", errors.Wrap(err, "inline")
 		}
@@ -66,12 +88,15 @@ func SVG(d Refer) func(string) (template.HTML, error) {
 	}
 }
 
-// Ref marks the provided file as a dependency of the template, so any changes to that file
-// will trigger a rebuild. It returns no output.
-func Ref(r Refer) func(string) string {
+// Ref marks the provided file, relative to the template's base directory, as a
+// dependency of the template, so any changes to that file will trigger a rebuild. It
+// returns no output.
+func Ref(r FilesystemRefer) func(string) string {
 	return func(filePath string) string {
-		if _, err := os.Stat(filePath); err != nil {
-			log.Printf("ref: os: stat: %s", err)
+		filePath = FSPath(r.FS(), r.BaseDir(), filePath)
+
+		if _, err := fs.Stat(r.FS(), filePath); err != nil {
+			log.Printf("ref: fs: stat: %s", err)
 		}
 
 		r.Ref(filePath)
@@ -0,0 +1,103 @@
+package tmplfunc
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// testOSFS is an OSFS which opens files from the operating system's filesystem.
+type testOSFS struct{}
+
+func (testOSFS) OSPaths() {}
+
+func (testOSFS) Open(name string) (fs.File, error) { return os.Open(name) }
+
+func TestFSPath(t *testing.T) {
+	site, err := filepath.Abs("site")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fsys    fs.FS
+		base, p string
+		want    string
+	}{
+		{"os relative", testOSFS{}, "site", "posts/a.md", filepath.Join(site, "posts", "a.md")},
+		{"os absolute base", testOSFS{}, site, "a.md", filepath.Join(site, "a.md")},
+		{"os absolute", testOSFS{}, "site", filepath.FromSlash("/data/a.md"), filepath.FromSlash("/data/a.md")},
+		{"os without base", testOSFS{}, "", "a.md", "a.md"},
+		{"fs relative", fstest.MapFS{}, "site", "posts/a.md", "site/posts/a.md"},
+		{"fs without base", fstest.MapFS{}, "", "posts/a.md", "posts/a.md"},
+		{"fs absolute base", fstest.MapFS{}, filepath.FromSlash("/srv/site"), "a.md", "a.md"},
+		{"fs absolute path", fstest.MapFS{}, "site", "/a.md", "a.md"},
+		{"fs dots", fstest.MapFS{}, "./site", "posts/../a.md", "site/a.md"},
+		{"fs escape", fstest.MapFS{}, "site", "../../a.md", "a.md"},
+	}
+
+	for _, test := range tests {
+		if got := FSPath(test.fsys, test.base, test.p); got != test.want {
+			t.Errorf("%s: FSPath(%q, %q) = %q, want %q", test.name, test.base, test.p, got, test.want)
+		}
+
+		if _, ok := test.fsys.(OSFS); !ok && !fs.ValidPath(FSPath(test.fsys, test.base, test.p)) {
+			t.Errorf("%s: FSPath(%q, %q) isn't a valid fs.FS path", test.name, test.base, test.p)
+		}
+	}
+}
+
+// testFilesystem is a FilesystemRefer which records the refs it's given.
+type testFilesystem struct {
+	fsys fs.FS
+	base string
+	refs []string
+}
+
+func (f *testFilesystem) FS() fs.FS          { return f.fsys }
+func (f *testFilesystem) BaseDir() string    { return f.base }
+func (f *testFilesystem) Ref(p string) error { f.refs = append(f.refs, p); return nil }
+
+func TestFilesResolveAgainstBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "data", "a.json"), []byte(`{"a": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(dir, "data", "a.json")
+
+	for _, f := range []*testFilesystem{
+		{fsys: testOSFS{}, base: dir},
+		{fsys: fstest.MapFS{"site/data/a.json": {Data: []byte(`{"a": 1}`)}}, base: "site"},
+	} {
+		if _, ok := f.fsys.(OSFS); !ok {
+			want = "site/data/a.json"
+		}
+
+		if got, err := File(f)("data/a.json"); err != nil || got != `{"a": 1}` {
+			t.Errorf("%T: file = %q, %v, want %q", f.fsys, got, err, `{"a": 1}`)
+		}
+
+		if got, err := Inline(f)("data/a.json"); err != nil || got != `{"a": 1}` {
+			t.Errorf("%T: inline = %q, %v, want %q", f.fsys, got, err, `{"a": 1}`)
+		}
+
+		if got, err := GetJSON(f)("data/a.json"); err != nil || !reflect.DeepEqual(got, map[string]interface{}{"a": 1.0}) {
+			t.Errorf("%T: getJSON = %v, %v, want map[a:1]", f.fsys, got, err)
+		}
+
+		Ref(f)("data/a.json")
+
+		if !reflect.DeepEqual(f.refs, []string{want, want}) {
+			t.Errorf("%T: refs = %q, want %q twice", f.fsys, f.refs, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/pkg/errors"
+)
+
+// DefaultHighlightStyle is the chroma style used when none is configured.
+const DefaultHighlightStyle = "github"
+
+// highlightStyle returns the chroma style with the provided name, or the default style
+// if name is empty.
+func highlightStyle(name string) (*chroma.Style, error) {
+	if name == "" {
+		name = DefaultHighlightStyle
+	}
+
+	style, ok := styles.Registry[strings.ToLower(name)]
+	if !ok {
+		names := styles.Names()
+		sort.Strings(names)
+		return nil, errors.Errorf("unknown highlight style %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	return style, nil
+}
+
+// highlightFormatOptions returns the chroma HTML formatter options for the provided
+// Markdown options.
+func highlightFormatOptions(opts MarkdownOptions) []chromahtml.Option {
+	return []chromahtml.Option{
+		chromahtml.WithClasses(opts.HighlightClasses),
+		chromahtml.WithLineNumbers(opts.LineNumbers),
+	}
+}
+
+// Highlight returns a function which highlights the provided code as HTML, using the
+// lexer for the provided language (or guessing it, if the language is unknown) and the
+// highlight style and options from the template's Markdown options.
+func Highlight(m Markdowner) func(string, string) (template.HTML, error) {
+	return func(lang string, code string) (template.HTML, error) {
+		opts := m.MarkdownOptions()
+
+		style, err := highlightStyle(opts.HighlightStyle)
+		if err != nil {
+			return "", err
+		}
+
+		lexer := lexers.Get(lang)
+		if lexer == nil {
+			lexer = lexers.Analyse(code)
+		}
+		if lexer == nil {
+			lexer = lexers.Fallback
+		}
+
+		it, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+		if err != nil {
+			return "", errors.Wrap(err, "chroma: tokenise")
+		}
+
+		buf := bytes.Buffer{}
+		if err := chromahtml.New(highlightFormatOptions(opts)...).Format(&buf, style, it); err != nil {
+			return "", errors.Wrap(err, "chroma: format")
+		}
+
+		return template.HTML(buf.String()), nil
+	}
+}
+
+// HighlightCSS returns the stylesheet for code highlighted with the provided style when
+// HighlightClasses is set.
+func HighlightCSS(name string) (template.CSS, error) {
+	style, err := highlightStyle(name)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.Buffer{}
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&buf, style); err != nil {
+		return "", errors.Wrap(err, "chroma: write css")
+	}
+
+	return template.CSS(buf.String()), nil
+}
@@ -0,0 +1,177 @@
+package tmplfunc
+
+import (
+	stdhtml "html"
+	"html/template"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/html"
+)
+
+// voidElements are the HTML elements which never have a closing tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "param": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// StripHTML removes every HTML tag and comment from s, along with the contents of
+// script and style elements, leaving only the text. Character references like &amp; are
+// unescaped, so the result is plain text.
+func StripHTML(s interface{}) string {
+	l := html.NewLexer(parse.NewInputString(toString(s)))
+	b := strings.Builder{}
+
+	skip := false
+	for {
+		tt, data := l.Next()
+		switch tt {
+		case html.ErrorToken:
+			return b.String()
+		case html.StartTagToken:
+			name := strings.ToLower(string(l.Text()))
+			skip = name == "script" || name == "style"
+		case html.EndTagToken:
+			skip = false
+		case html.TextToken:
+			if !skip {
+				b.WriteString(stdhtml.UnescapeString(string(data)))
+			}
+		}
+	}
+}
+
+// excerptTags are the elements Excerpt keeps. Other tags are dropped, along with the
+// contents of script and style elements.
+var excerptTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "br": true, "cite": true,
+	"code": true, "del": true, "em": true, "i": true, "ins": true, "kbd": true, "li": true,
+	"mark": true, "ol": true, "p": true, "pre": true, "q": true, "s": true, "small": true,
+	"strong": true, "sub": true, "sup": true, "u": true, "ul": true,
+}
+
+// Excerpt returns the beginning of the provided HTML, keeping at most n characters of
+// text, where a character reference like &amp; is one character. The text is cut at a
+// word boundary and followed by an ellipsis, and any elements left open are closed. The
+// HTML is sanitized: only the elements in excerptTags are kept, without attributes
+// except for a link's href, which must be relative or use http, https or mailto.
+func Excerpt(n int, s interface{}) (template.HTML, error) {
+	if n < 0 {
+		return "", errors.Errorf("excerpt: expected a non-negative length, got %d", n)
+	}
+
+	l := html.NewLexer(parse.NewInputString(toString(s)))
+	b := strings.Builder{}
+
+	var open []string
+	tag, href := "", ""
+	skip := false
+	remaining := n
+	for {
+		tt, data := l.Next()
+		switch tt {
+		case html.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+
+			return template.HTML(b.String()), nil
+		case html.StartTagToken:
+			tag, href = strings.ToLower(string(l.Text())), ""
+			if tag == "script" || tag == "style" {
+				skip = true
+			}
+		case html.AttributeToken:
+			if tag == "a" && strings.EqualFold(string(l.Text()), "href") {
+				href = stdhtml.UnescapeString(strings.Trim(string(l.AttrVal()), `"'`))
+			}
+		case html.StartTagCloseToken, html.StartTagVoidToken:
+			if !excerptTags[tag] {
+				break
+			}
+
+			b.WriteString("<" + tag)
+			if tag == "a" && href != "" && safeURL(href) {
+				b.WriteString(` href="` + template.HTMLEscapeString(href) + `"`)
+			}
+			b.WriteString(">")
+
+			if tt == html.StartTagCloseToken && !voidElements[tag] {
+				open = append(open, tag)
+			}
+		case html.EndTagToken:
+			name := strings.ToLower(string(l.Text()))
+			if name == "script" || name == "style" {
+				skip = false
+			}
+
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		case html.TextToken:
+			if skip {
+				break
+			}
+
+			text := []rune(stdhtml.UnescapeString(string(data)))
+			if len(text) <= remaining {
+				remaining -= len(text)
+				b.WriteString(template.HTMLEscapeString(string(text)))
+				break
+			}
+
+			cut := strings.TrimRightFunc(cutText(text, remaining, remaining == n), unicode.IsSpace)
+			b.WriteString(template.HTMLEscapeString(cut))
+			b.WriteString("…")
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+
+			return template.HTML(b.String()), nil
+		}
+	}
+}
+
+// safeURL reports whether the URL u is relative or uses the http, https or mailto
+// scheme.
+func safeURL(u string) bool {
+	u = strings.TrimSpace(u)
+
+	i := strings.IndexAny(u, ":/?#")
+	if i < 0 || u[i] != ':' {
+		return true
+	}
+
+	switch strings.ToLower(u[:i]) {
+	case "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
+
+// cutText returns the first n characters of text, which must be longer than n, cut at
+// the last word boundary. If there's no word boundary, the whole word is dropped, unless
+// hard is set, in which case the word is cut.
+func cutText(text []rune, n int, hard bool) string {
+	s := string(text[:n])
+
+	if !unicode.IsSpace(text[n]) {
+		if i := strings.LastIndexFunc(s, unicode.IsSpace); i >= 0 {
+			s = s[:i]
+		} else if !hard {
+			s = ""
+		}
+	}
+
+	return s
+}
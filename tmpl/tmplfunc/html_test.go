@@ -0,0 +1,68 @@
+package tmplfunc
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"<p>Tom &amp; <b>Jerry</b></p>", "Tom & Jerry"},
+		{"&lt;b&gt; is bold &#x2014; &quot;really&quot;", `<b> is bold — "really"`},
+		{"<!-- comment -->a<br/>b", "ab"},
+		{"<p>x</p><script>alert(1)</script><style>p{}</style>y", "xy"},
+	}
+
+	for _, test := range tests {
+		if got := StripHTML(test.in); got != test.want {
+			t.Errorf("StripHTML(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		n    int
+		in   string
+		want template.HTML
+	}{
+		{100, "<p>Hello <b>world</b></p>", "<p>Hello <b>world</b></p>"},
+		{8, "<p>Hello <b>world</b></p>", "<p>Hello <b>…</b></p>"},
+		{13, "<p>Hello there, world</p>", "<p>Hello there,…</p>"},
+		{3, "Supercalifragilistic", "Sup…"},
+		{11, "Tom &amp; Jerry", "Tom &amp; Jerry"},
+		{5, "Tom &amp; Jerry", "Tom &amp;…"},
+		{50, "a &lt;b&gt; c", "a &lt;b&gt; c"},
+		{50, "<p><em>unclosed", "<p><em>unclosed</em></p>"},
+		{50, `<p onclick="steal()">Hi<script>alert(1)</script></p>`, "<p>Hi</p>"},
+		{50, `<div class="x"><img src=x onerror=alert(1)>text</div>`, "text"},
+		{50, `<a href="/about" class="c">x</a>`, `<a href="/about">x</a>`},
+		{50, `<a href="https://example.com/?a=1&amp;b=2">x</a>`, `<a href="https://example.com/?a=1&amp;b=2">x</a>`},
+		{50, `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{50, `<a href=" JavaScript:alert(1)">x</a>`, "<a>x</a>"},
+		{50, `<a href="data:text/html,x">x</a>`, "<a>x</a>"},
+	}
+
+	for _, test := range tests {
+		got, err := Excerpt(test.n, test.in)
+		if err != nil {
+			t.Errorf("Excerpt(%d, %q): %s", test.n, test.in, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("Excerpt(%d, %q) = %q, want %q", test.n, test.in, got, test.want)
+		}
+	}
+
+	if got, err := Excerpt(0, "Hello"); err != nil || got != "…" {
+		t.Errorf("Excerpt(0, \"Hello\") = %q, %v, want %q", got, err, "…")
+	}
+
+	if _, err := Excerpt(-3, "Hello"); err == nil {
+		t.Errorf("Excerpt(-3, \"Hello\"): expected an error")
+	}
+}
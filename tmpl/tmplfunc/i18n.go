@@ -0,0 +1,378 @@
+package tmplfunc
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Localizer is a template which is rendered for a particular locale.
+type Localizer interface {
+	Locale() string
+	Translations() *Translations
+}
+
+// pluralCategories are the CLDR plural categories a message may define.
+var pluralCategories = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+// Translations holds message catalogs for a set of locales.
+type Translations struct {
+	// Fallback is the locale used when a message is missing from a template's locale.
+	Fallback string
+
+	// Strict makes a missing message an error, rather than just a logged warning.
+	Strict bool
+
+	// catalogs maps each locale to its messages, keyed by dotted path. A message is
+	// either a string or a map of plural categories to strings.
+	catalogs map[string]map[string]interface{}
+	files    map[string]string
+
+	mu      sync.Mutex
+	missing map[string]struct{}
+}
+
+// NewTranslations returns Translations for the provided catalogs, which map each locale
+// to its (possibly nested) messages.
+func NewTranslations(catalogs map[string]map[string]interface{}) *Translations {
+	tr := &Translations{
+		catalogs: map[string]map[string]interface{}{},
+		files:    map[string]string{},
+		missing:  map[string]struct{}{},
+	}
+
+	for locale, messages := range catalogs {
+		tr.catalogs[normalizeLocale(locale)] = flattenMessages(messages)
+	}
+
+	return tr
+}
+
+// LoadTranslations reads a message catalog for each .json, .yaml or .yml file in dir.
+// Each file is named after its locale, like fr.json or pt-BR.yaml.
+func LoadTranslations(fsys fs.FS, dir string) (*Translations, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read locales dir (path: %s)", dir)
+	}
+
+	tr := NewTranslations(nil)
+	for _, e := range entries {
+		ext := path.Ext(e.Name())
+		if e.IsDir() || ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		file := path.Join(dir, e.Name())
+		by, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read catalog (path: %s)", file)
+		}
+
+		messages := map[string]interface{}{}
+		if ext == ".json" {
+			err = json.Unmarshal(by, &messages)
+		} else {
+			err = yaml.Unmarshal(by, &messages)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "decode catalog (path: %s)", file)
+		}
+
+		locale := normalizeLocale(strings.TrimSuffix(e.Name(), ext))
+		tr.catalogs[locale] = flattenMessages(messages)
+		tr.files[locale] = file
+	}
+
+	return tr, nil
+}
+
+// Locales returns the locales with a catalog, sorted.
+func (tr *Translations) Locales() []string {
+	tbr := make([]string, 0, len(tr.catalogs))
+	for locale := range tr.catalogs {
+		tbr = append(tbr, locale)
+	}
+
+	sort.Strings(tbr)
+	return tbr
+}
+
+// Missing returns every message which was requested but missing, as "locale: key".
+func (tr *Translations) Missing() []string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tbr := make([]string, 0, len(tr.missing))
+	for k := range tr.missing {
+		tbr = append(tbr, k)
+	}
+
+	sort.Strings(tbr)
+	return tbr
+}
+
+// candidates returns the locales to look up a message in, in order: the locale itself,
+// its language, and the fallback locale.
+func (tr *Translations) candidates(locale string) []string {
+	locale = normalizeLocale(locale)
+	tbr := []string{locale}
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		tbr = append(tbr, lang)
+	}
+
+	if fb := normalizeLocale(tr.Fallback); fb != "" {
+		tbr = append(tbr, fb)
+	}
+
+	return tbr
+}
+
+// Translate returns the message at key in the provided locale, with placeholders like
+// {name} replaced by the corresponding value in data. If the message has plural forms,
+// the form is chosen by data["count"]. A message which isn't in the locale or its
+// language is missing: it's reported once and, unless tr is strict, replaced by the
+// fallback locale's message or the key itself.
+func (tr *Translations) Translate(locale, key string, data map[string]interface{}) (string, error) {
+	var msg interface{}
+	var found string
+	for _, l := range tr.candidates(locale) {
+		if m, ok := tr.catalogs[l][key]; ok {
+			msg, found = m, l
+			break
+		}
+	}
+
+	// Falling back from a regional locale to its language, like pt-BR to pt, is
+	// expected; only falling back further is a missing message.
+	if lang, _, _ := strings.Cut(normalizeLocale(locale), "-"); found != normalizeLocale(locale) && found != lang {
+		if err := tr.reportMissing(locale, key); err != nil {
+			return "", err
+		}
+	}
+
+	if msg == nil {
+		return key, nil
+	}
+
+	if forms, ok := msg.(map[string]interface{}); ok {
+		n, err := toNumber(data["count"])
+		if err != nil {
+			return "", errors.Wrapf(err, "t: %s: count", key)
+		}
+
+		form, ok := forms[pluralCategory(found, n.float())]
+		if n.float() == 0 && forms["zero"] != nil {
+			form, ok = forms["zero"], true
+		}
+		if !ok {
+			form = forms["other"]
+		}
+
+		msg = form
+	}
+
+	return interpolate(toString(msg), locale, data), nil
+}
+
+func (tr *Translations) reportMissing(locale, key string) error {
+	if tr.Strict {
+		return errors.Errorf("t: missing translation (locale: %s, key: %s)", locale, key)
+	}
+
+	id := locale + ": " + key
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if _, ok := tr.missing[id]; !ok {
+		tr.missing[id] = struct{}{}
+		log.Printf("t: missing translation (locale: %s, key: %s)", locale, key)
+	}
+
+	return nil
+}
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// interpolate replaces placeholders like {name} in msg with values from data. Numbers are
+// formatted for the provided locale; unknown placeholders are left as-is.
+func interpolate(msg, locale string, data map[string]interface{}) string {
+	return placeholderPattern.ReplaceAllStringFunc(msg, func(m string) string {
+		v, ok := data[m[1:len(m)-1]]
+		if !ok {
+			return m
+		}
+
+		if n, err := toNumber(v); err == nil && !isString(v) {
+			return numberFormatFor(locale).formatNumber(n)
+		}
+
+		return toString(v)
+	})
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// flattenMessages flattens nested catalogs into dotted keys. Maps whose keys are all
+// plural categories, including "other", are kept as plural forms.
+func flattenMessages(m map[string]interface{}) map[string]interface{} {
+	tbr := map[string]interface{}{}
+
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+
+			child, ok := v.(map[string]interface{})
+			if !ok {
+				tbr[key] = v
+				continue
+			}
+
+			if isPlural(child) {
+				tbr[key] = child
+				continue
+			}
+
+			walk(key, child)
+		}
+	}
+
+	walk("", m)
+	return tbr
+}
+
+func isPlural(m map[string]interface{}) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+
+	for k := range m {
+		if !pluralCategories[k] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeLocale(locale string) string {
+	lang, region, ok := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	if !ok {
+		return strings.ToLower(lang)
+	}
+
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+// pluralCategory returns the CLDR plural category of n in the provided locale, for
+// cardinal numbers.
+func pluralCategory(locale string, n float64) string {
+	lang, _, _ := strings.Cut(normalizeLocale(locale), "-")
+	i := int64(n)
+	integer := float64(i) == n
+
+	switch lang {
+	case "ja", "ko", "zh", "th", "vi", "id":
+		return "other"
+	case "fr":
+		if i == 0 || i == 1 {
+			return "one"
+		}
+		return "other"
+	case "pt":
+		if locale == "pt-PT" {
+			break
+		}
+		if i == 0 || i == 1 {
+			return "one"
+		}
+		return "other"
+	case "ru", "uk":
+		if !integer {
+			return "other"
+		}
+		switch {
+		case i%10 == 1 && i%100 != 11:
+			return "one"
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		if !integer {
+			return "other"
+		}
+		switch {
+		case i == 1:
+			return "one"
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return "few"
+		}
+		return "many"
+	}
+
+	if integer && i == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// Translate returns a function which looks up messages in the template's translations
+// for its locale. It takes the message key and either a map of placeholder values, a
+// count, or key/value pairs:
+//
+//	t "nav.home"
+//	t "inbox.unread" 3
+//	t "greeting" "name" .Params.name
+//	t "greeting" (dict "name" .Params.name)
+//
+// The catalog the message comes from is marked as updateable.
+func Translate(l LocalizerRefer) func(string, ...interface{}) (string, error) {
+	return func(key string, args ...interface{}) (string, error) {
+		tr := l.Translations()
+		if tr == nil {
+			tr = NewTranslations(nil)
+		}
+
+		var data map[string]interface{}
+		switch {
+		case len(args) == 1 && isMap(args[0]):
+			data = args[0].(map[string]interface{})
+		case len(args) == 1:
+			data = map[string]interface{}{"count": args[0]}
+		default:
+			data = Dict(args...)
+		}
+
+		for _, locale := range tr.candidates(l.Locale()) {
+			if file, ok := tr.files[locale]; ok {
+				l.Ref(file)
+			}
+		}
+
+		return tr.Translate(l.Locale(), key, data)
+	}
+}
+
+func isMap(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
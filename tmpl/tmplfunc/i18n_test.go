@@ -0,0 +1,147 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testTranslations() *Translations {
+	tr := NewTranslations(map[string]map[string]interface{}{
+		"en": {
+			"nav":      map[string]interface{}{"home": "Home"},
+			"greeting": "Hello, {name}!",
+			"inbox": map[string]interface{}{
+				"unread": map[string]interface{}{"zero": "No messages", "one": "{count} message", "other": "{count} messages"},
+			},
+		},
+		"pt":    {"nav": map[string]interface{}{"home": "Início"}},
+		"pt_br": {"nav": map[string]interface{}{"about": "Sobre"}},
+	})
+	tr.Fallback = "en"
+
+	return tr
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		locale, key string
+		data        map[string]interface{}
+		want        string
+	}{
+		{"en", "nav.home", nil, "Home"},
+		{"en", "greeting", map[string]interface{}{"name": "Ana"}, "Hello, Ana!"},
+		{"en", "greeting", nil, "Hello, {name}!"},
+		{"en", "inbox.unread", map[string]interface{}{"count": 0}, "No messages"},
+		{"en", "inbox.unread", map[string]interface{}{"count": 1}, "1 message"},
+		{"en", "inbox.unread", map[string]interface{}{"count": 1234}, "1,234 messages"},
+		{"pt-BR", "nav.about", nil, "Sobre"},
+		{"pt-BR", "nav.home", nil, "Início"},
+		{"pt-BR", "greeting", map[string]interface{}{"name": "Ana"}, "Hello, Ana!"},
+		{"de", "nav.home", nil, "Home"},
+		{"en", "nav.missing", nil, "nav.missing"},
+	}
+
+	tr := testTranslations()
+	for _, test := range tests {
+		got, err := tr.Translate(test.locale, test.key, test.data)
+		if err != nil {
+			t.Errorf("Translate(%s, %s): %s", test.locale, test.key, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("Translate(%s, %s) = %q, want %q", test.locale, test.key, got, test.want)
+		}
+	}
+
+	want := []string{"de: nav.home", "en: nav.missing", "pt-BR: greeting"}
+	if got := tr.Missing(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing() = %v, want %v", got, want)
+	}
+}
+
+func TestTranslateStrict(t *testing.T) {
+	tr := testTranslations()
+	tr.Strict = true
+
+	tests := []struct {
+		locale, key string
+		missing     bool
+	}{
+		{"en", "nav.home", false},
+		{"pt", "nav.home", false},
+		{"pt-BR", "nav.home", false},
+		{"pt-BR", "nav.about", false},
+		{"pt-BR", "greeting", true},
+		{"pt", "nav.about", true},
+		{"de", "nav.home", true},
+	}
+
+	for _, test := range tests {
+		_, err := tr.Translate(test.locale, test.key, nil)
+		if test.missing && err == nil {
+			t.Errorf("Translate(%s, %s): expected a missing translation error", test.locale, test.key)
+		} else if !test.missing && err != nil {
+			t.Errorf("Translate(%s, %s): %s", test.locale, test.key, err)
+		}
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      float64
+		want   string
+	}{
+		{"en", 0, "other"},
+		{"en", 1, "one"},
+		{"en", 1.5, "other"},
+		{"fr", 0, "one"},
+		{"fr", 2, "other"},
+		{"pt", 0, "one"},
+		{"pt-PT", 0, "other"},
+		{"ja", 1, "other"},
+		{"ru", 1, "one"},
+		{"ru", 3, "few"},
+		{"ru", 5, "many"},
+		{"ru", 11, "many"},
+		{"ru", 21, "one"},
+		{"ru", 1.5, "other"},
+		{"pl", 1, "one"},
+		{"pl", 22, "few"},
+		{"pl", 25, "many"},
+		{"pl", 21, "many"},
+	}
+
+	for _, test := range tests {
+		if got := pluralCategory(test.locale, test.n); got != test.want {
+			t.Errorf("pluralCategory(%s, %v) = %q, want %q", test.locale, test.n, got, test.want)
+		}
+	}
+}
+
+func TestLoadTranslations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json":    {Data: []byte(`{"nav": {"home": "Home"}}`)},
+		"locales/pt_BR.yaml": {Data: []byte("nav:\n  home: Início\n")},
+		"locales/README.md":  {Data: []byte("# Catalogs")},
+	}
+
+	tr, err := LoadTranslations(fsys, "locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := tr.Locales(), []string{"en", "pt-BR"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Locales() = %v, want %v", got, want)
+	}
+
+	if got, err := tr.Translate("pt-BR", "nav.home", nil); err != nil || got != "Início" {
+		t.Errorf("Translate(pt-BR, nav.home) = %q, %v, want %q", got, err, "Início")
+	}
+
+	if _, err := LoadTranslations(fsys, "missing"); err == nil {
+		t.Errorf("LoadTranslations(missing): expected an error")
+	}
+}
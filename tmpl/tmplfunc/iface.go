@@ -1,14 +1,13 @@
 package tmplfunc
 
-import "os"
+import "io/fs"
 
 type Refer interface {
 	Ref(string) error
 }
 
 type Filesystem interface {
-	In() *os.File
-	Out() *os.File
+	FS() fs.FS
 	BaseDir() string
 }
 
@@ -17,6 +16,11 @@ type FilesystemRefer interface {
 	Filesystem
 }
 
+type LocalizerRefer interface {
+	Refer
+	Localizer
+}
+
 type Moder interface {
 	IsProduction() bool
 }
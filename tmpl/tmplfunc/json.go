@@ -1,37 +1,75 @@
 package tmplfunc
 
 import (
+	"bytes"
 	"encoding/json"
-	"io/ioutil"
-	"path/filepath"
+	"io/fs"
 
 	"github.com/pkg/errors"
 )
 
-// GetJSON reads data from the provided (local) path, and attempts to unmarshal it.
-func GetJSON(path string) (interface{}, error) {
-	abspath, _ := filepath.Abs(path)
+// GetJSON returns a function which reads data from the provided path, relative to the
+// template's base directory, and attempts to unmarshal it.
+func GetJSON(f Filesystem) func(string) (interface{}, error) {
+	return func(path string) (interface{}, error) {
+		path = FSPath(f.FS(), f.BaseDir(), path)
 
-	by, err := ioutil.ReadFile(path)
+		by, err := fs.ReadFile(f.FS(), path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read file (path: %s)", path)
+		}
+
+		var target interface{}
+		err = json.Unmarshal(by, &target)
+		if err != nil {
+			return nil, err
+		}
+
+		return target, nil
+	}
+}
+
+// JSONify marshals the provided interface into its JSON representation.
+func JSONify(v interface{}) (string, error) {
+	by, err := json.Marshal(v)
 	if err != nil {
-		return nil, errors.Wrapf(err, "read file (path: %s, abs path: %s)", path, abspath)
+		return "", err
 	}
 
-	var target interface{}
-	err = json.Unmarshal(by, &target)
+	return string(by), nil
+}
+
+// ToPrettyJSON marshals the provided interface into its JSON representation, indented by
+// two spaces.
+func ToPrettyJSON(v interface{}) (string, error) {
+	by, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return target, nil
+	return string(by), nil
 }
 
-// JSONify marshals the provided interface into its JSON representation.
-func JSONify(v interface{}) (string, error) {
+// ToJSONSorted marshals the provided interface into its JSON representation with every
+// object's keys sorted, including the fields of structs.
+func ToJSONSorted(v interface{}) (string, error) {
 	by, err := json.Marshal(v)
 	if err != nil {
 		return "", err
 	}
 
+	d := json.NewDecoder(bytes.NewReader(by))
+	d.UseNumber()
+
+	var target interface{}
+	if err := d.Decode(&target); err != nil {
+		return "", err
+	}
+
+	by, err = json.Marshal(target)
+	if err != nil {
+		return "", err
+	}
+
 	return string(by), nil
 }
@@ -0,0 +1,34 @@
+package tmplfunc
+
+import "testing"
+
+func TestToJSON(t *testing.T) {
+	v := map[string]interface{}{
+		"b": struct {
+			Z int    `json:"z"`
+			Y string `json:"y"`
+		}{1, "<y>"},
+		"a": []interface{}{1.5, nil},
+	}
+
+	tests := []struct {
+		name string
+		fn   func(interface{}) (string, error)
+		want string
+	}{
+		{"jsonify", JSONify, `{"a":[1.5,null],"b":{"z":1,"y":"\u003cy\u003e"}}`},
+		{"toPrettyJSON", ToPrettyJSON, "{\n  \"a\": [\n    1.5,\n    null\n  ],\n  \"b\": {\n    \"z\": 1,\n    \"y\": \"\\u003cy\\u003e\"\n  }\n}"},
+		{"toJSONSorted", ToJSONSorted, `{"a":[1.5,null],"b":{"y":"\u003cy\u003e","z":1}}`},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(v)
+		if err != nil || got != test.want {
+			t.Errorf("%s = %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := ToJSONSorted(func() {}); err == nil {
+		t.Errorf("toJSONSorted of a func: expected an error")
+	}
+}
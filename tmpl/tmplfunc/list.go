@@ -0,0 +1,200 @@
+package tmplfunc
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// List returns its arguments as a list.
+func List(v ...interface{}) []interface{} {
+	if v == nil {
+		return []interface{}{}
+	}
+
+	return v
+}
+
+// First returns the first element of the provided list, or nil if it's empty.
+func First(v interface{}) (interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[0], nil
+}
+
+// Last returns the last element of the provided list, or nil if it's empty.
+func Last(v interface{}) (interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[len(list)-1], nil
+}
+
+// Rest returns every element of the provided list except the first.
+func Rest(v interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[1:], nil
+}
+
+// Initial returns every element of the provided list except the last.
+func Initial(v interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	return list[:len(list)-1], nil
+}
+
+// Append returns a new list with el added to the end of the provided list.
+func Append(v interface{}, el interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(make([]interface{}, 0, len(list)+1), list...), el), nil
+}
+
+// Prepend returns a new list with el added to the beginning of the provided list.
+func Prepend(v interface{}, el interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]interface{}{el}, list...), nil
+}
+
+// Concat returns a new list with the elements of each of the provided lists.
+func Concat(lists ...interface{}) ([]interface{}, error) {
+	tbr := []interface{}{}
+	for _, v := range lists {
+		list, err := toSlice(v)
+		if err != nil {
+			return nil, err
+		}
+
+		tbr = append(tbr, list...)
+	}
+
+	return tbr, nil
+}
+
+// Uniq returns a new list with duplicate elements removed.
+func Uniq(v interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := []interface{}{}
+	for _, el := range list {
+		if !inList(tbr, el) {
+			tbr = append(tbr, el)
+		}
+	}
+
+	return tbr, nil
+}
+
+// Reverse returns a new list with the elements of the provided list in reverse order.
+func Reverse(v interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := make([]interface{}, len(list))
+	for i, el := range list {
+		tbr[len(list)-1-i] = el
+	}
+
+	return tbr, nil
+}
+
+// Has reports whether the provided list contains el.
+func Has(el interface{}, v interface{}) (bool, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return false, err
+	}
+
+	return inList(list, el), nil
+}
+
+// Without returns a new list without any of the provided elements.
+func Without(v interface{}, omit ...interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := []interface{}{}
+	for _, el := range list {
+		if !inList(omit, el) {
+			tbr = append(tbr, el)
+		}
+	}
+
+	return tbr, nil
+}
+
+// Compact returns a new list without any empty elements.
+func Compact(v interface{}) ([]interface{}, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return nil, err
+	}
+
+	tbr := []interface{}{}
+	for _, el := range list {
+		if !Empty(el) {
+			tbr = append(tbr, el)
+		}
+	}
+
+	return tbr, nil
+}
+
+func inList(list []interface{}, el interface{}) bool {
+	for _, v := range list {
+		if reflect.DeepEqual(v, el) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toSlice converts any slice or array into a []interface{}. A nil value is an empty list.
+func toSlice(v interface{}) ([]interface{}, error) {
+	if v == nil {
+		return []interface{}{}, nil
+	}
+
+	if list, ok := v.([]interface{}); ok {
+		return list, nil
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		tbr := make([]interface{}, val.Len())
+		for i := range tbr {
+			tbr[i] = val.Index(i).Interface()
+		}
+		return tbr, nil
+	default:
+		return nil, errors.Errorf("expected a list, got %T", v)
+	}
+}
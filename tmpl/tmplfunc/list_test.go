@@ -0,0 +1,60 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListFuncs(t *testing.T) {
+	list := []interface{}{1, "a", 2.5}
+
+	tests := []struct {
+		name string
+		fn   func() (interface{}, error)
+		want interface{}
+	}{
+		{"first", func() (interface{}, error) { return First(list) }, 1},
+		{"first of empty", func() (interface{}, error) { return First(nil) }, nil},
+		{"last", func() (interface{}, error) { return Last([]string{"x", "y"}) }, "y"},
+		{"rest", func() (interface{}, error) { return Rest(list) }, []interface{}{"a", 2.5}},
+		{"initial", func() (interface{}, error) { return Initial(list) }, []interface{}{1, "a"}},
+		{"append", func() (interface{}, error) { return Append([]int{1}, 2) }, []interface{}{1, 2}},
+		{"prepend", func() (interface{}, error) { return Prepend([]int{1}, 0) }, []interface{}{0, 1}},
+		{"concat", func() (interface{}, error) { return Concat([]int{1}, nil, []string{"a"}) }, []interface{}{1, "a"}},
+		{"uniq", func() (interface{}, error) { return Uniq([]interface{}{1, 2, 1, "1"}) }, []interface{}{1, 2, "1"}},
+		{"reverse", func() (interface{}, error) { return Reverse([]int{1, 2, 3}) }, []interface{}{3, 2, 1}},
+		{"has", func() (interface{}, error) { return Has("a", list) }, true},
+		{"has not", func() (interface{}, error) { return Has(2, list) }, false},
+		{"without", func() (interface{}, error) { return Without(list, "a", 3) }, []interface{}{1, 2.5}},
+		{"compact", func() (interface{}, error) { return Compact([]interface{}{0, "", nil, "a", false, 1}) }, []interface{}{"a", 1}},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn()
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (interface{}, error)
+	}{
+		{"first of a non-list", func() (interface{}, error) { return First(5) }},
+		{"rest of a map", func() (interface{}, error) { return Rest(map[string]int{"a": 1}) }},
+		{"concat with a non-list", func() (interface{}, error) { return Concat([]int{1}, 5) }},
+	}
+
+	for _, test := range tests {
+		if _, err := test.fn(); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
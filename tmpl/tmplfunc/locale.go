@@ -0,0 +1,157 @@
+package tmplfunc
+
+import (
+	"strings"
+	"time"
+)
+
+// localeNames holds month and weekday names for a language.
+type localeNames struct {
+	months      [12]string
+	shortMonths [12]string
+	days        [7]string
+	shortDays   [7]string
+}
+
+var names = map[string]localeNames{
+	"en": {
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	"de": {
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	},
+	"es": {
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	"fr": {
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	"it": {
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	"ja": {
+		months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		days:        [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		shortDays:   [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	},
+	"nl": {
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+	"pt": {
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+		days:        [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		shortDays:   [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+	},
+}
+
+// namesFor returns the names for the provided locale, like "fr" or "fr-CA", falling back
+// to English if the language isn't known.
+func namesFor(locale string) localeNames {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+	if n, ok := names[lang]; ok {
+		return n
+	}
+
+	return names["en"]
+}
+
+// MonthName returns the name of the provided time's month in the provided locale.
+func MonthName(locale string, t interface{}) (string, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return "", err
+	}
+
+	return namesFor(locale).months[tt.Month()-1], nil
+}
+
+// DayName returns the name of the provided time's weekday in the provided locale.
+func DayName(locale string, t interface{}) (string, error) {
+	tt, err := toTime(t)
+	if err != nil {
+		return "", err
+	}
+
+	return namesFor(locale).days[tt.Weekday()], nil
+}
+
+// layoutNames are the elements of a Go time layout which are written as words, longest
+// first so that "January" isn't mistaken for "Jan".
+var layoutNames = []string{"January", "Monday", "Jan", "Mon"}
+
+// layoutName returns the element of layoutNames at the start of layout, if there is one.
+// Like the time package, "Jan" and "Mon" aren't elements when they're followed by a
+// lowercase letter, so "Monaco" is written as-is.
+func layoutName(layout string) (string, bool) {
+	for _, name := range layoutNames {
+		if !strings.HasPrefix(layout, name) {
+			continue
+		}
+
+		if len(name) == 3 && len(layout) > 3 && 'a' <= layout[3] && layout[3] <= 'z' {
+			continue
+		}
+
+		return name, true
+	}
+
+	return "", false
+}
+
+// LocalFormatTime returns a function which formats the provided time with the provided
+// Go layout, like FormatTime, but writes month and weekday names in the template's
+// locale.
+func LocalFormatTime(l Localizer) func(string, time.Time) string {
+	return func(layout string, t time.Time) string {
+		n := namesFor(l.Locale())
+
+		b := strings.Builder{}
+		start := 0
+
+		for i := 0; i < len(layout); i++ {
+			name, ok := layoutName(layout[i:])
+			if !ok {
+				continue
+			}
+
+			b.WriteString(t.Format(layout[start:i]))
+
+			switch name {
+			case "January":
+				b.WriteString(n.months[t.Month()-1])
+			case "Jan":
+				b.WriteString(n.shortMonths[t.Month()-1])
+			case "Monday":
+				b.WriteString(n.days[t.Weekday()])
+			case "Mon":
+				b.WriteString(n.shortDays[t.Weekday()])
+			}
+
+			i += len(name) - 1
+			start = i + 1
+		}
+
+		b.WriteString(t.Format(layout[start:]))
+		return b.String()
+	}
+}
@@ -0,0 +1,76 @@
+package tmplfunc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalFormatTime(t *testing.T) {
+	// A Monday.
+	tm := time.Date(2024, time.March, 4, 15, 4, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{"en", "Monday, January 2, 2006", "Monday, March 4, 2024"},
+		{"fr", "Monday 2 January 2006", "lundi 4 mars 2024"},
+		{"fr-CA", "Mon 2 Jan", "lun. 4 mars"},
+		{"de", "Mon, 2. Jan 2006 15:04", "Mo., 4. März 2024 15:04"},
+		{"es", "Jan/Mon", "mar/lun"},
+		{"xx", "Jan 2", "Mar 4"},
+
+		// "Jan" and "Mon" followed by a lowercase letter aren't layout elements.
+		{"fr", "Monaco, Monday", "Monaco, lundi"},
+		{"fr", "Janus Jan", "Janus mars"},
+		{"fr", "MonJan", "lun.mars"},
+		{"fr", "Mon2", "lun.4"},
+		{"fr", "Jan_2", "mars 4"},
+		{"fr", "Mon.", "lun.."},
+	}
+
+	for _, test := range tests {
+		if got := LocalFormatTime(testLocalizer(test.locale))(test.layout, tm); got != test.want {
+			t.Errorf("localFormatTime(%s, %q) = %q, want %q", test.locale, test.layout, got, test.want)
+		}
+	}
+}
+
+func TestLocalFormatTimeMatchesFormat(t *testing.T) {
+	tm := time.Date(2024, time.March, 4, 15, 4, 5, 0, time.UTC)
+
+	for _, layout := range []string{
+		time.RFC1123, time.RFC850, time.ANSIC, time.Stamp, time.Kitchen,
+		"Monaco Janus", "Monthly January", "Mondays", "Jan2006", "Monday2",
+	} {
+		if got, want := LocalFormatTime(testLocalizer("en"))(layout, tm), tm.Format(layout); got != want {
+			t.Errorf("localFormatTime(en, %q) = %q, want %q", layout, got, want)
+		}
+	}
+}
+
+func TestMonthDayName(t *testing.T) {
+	tm := time.Date(2024, time.August, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		month  string
+		day    string
+	}{
+		{"en", "August", "Sunday"},
+		{"pt-BR", "agosto", "domingo"},
+		{"de_DE", "August", "Sonntag"},
+		{"zz", "August", "Sunday"},
+	}
+
+	for _, test := range tests {
+		if got, err := MonthName(test.locale, tm); err != nil || got != test.month {
+			t.Errorf("monthName(%s) = %q, %v, want %q", test.locale, got, err, test.month)
+		}
+
+		if got, err := DayName(test.locale, tm); err != nil || got != test.day {
+			t.Errorf("dayName(%s) = %q, %v, want %q", test.locale, got, err, test.day)
+		}
+	}
+}
@@ -2,33 +2,237 @@ package tmplfunc
 
 import (
 	"bytes"
+	"html"
+	"html/template"
+	"io/fs"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/parser"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
 )
 
-// Markdown converts the provided Markdown to HTML.
-func Markdown(in string) (string, error) {
+// MarkdownOptions configures how Markdown is converted to HTML. The zero value converts
+// CommonMark with typographic punctuation and passes raw HTML through.
+type MarkdownOptions struct {
+	// GFM enables every GitHub Flavored Markdown extension: tables, strikethrough, task
+	// lists and autolinks.
+	GFM bool
+
+	Tables          bool
+	Strikethrough   bool
+	TaskLists       bool
+	Autolinks       bool
+	Footnotes       bool
+	DefinitionLists bool
+
+	// HeadingIDs gives every heading an id attribute derived from its text, so that it
+	// can be linked to.
+	HeadingIDs bool
+
+	// EscapeHTML escapes raw HTML in the document instead of passing it through.
+	EscapeHTML bool
+
+	// Highlight highlights fenced code blocks with the chroma style named by
+	// HighlightStyle, using CSS classes instead of inline styles if HighlightClasses is
+	// set (see HighlightCSS). LineNumbers numbers the lines of every block; blocks can
+	// also set linenos and hl_lines attributes, like ```go {linenos=true,hl_lines=[2,"4-5"]}.
+	Highlight        bool
+	HighlightStyle   string
+	HighlightClasses bool
+	LineNumbers      bool
+}
+
+type Markdowner interface {
+	MarkdownOptions() MarkdownOptions
+}
+
+type MarkdownRefer interface {
+	FilesystemRefer
+	Markdowner
+}
+
+// newMarkdown returns a goldmark instance configured by opts.
+func newMarkdown(opts MarkdownOptions) goldmark.Markdown {
+	exts := []goldmark.Extender{extension.Typographer}
+
+	if opts.GFM {
+		exts = append(exts, extension.GFM)
+	} else {
+		if opts.Tables {
+			exts = append(exts, extension.Table)
+		}
+		if opts.Strikethrough {
+			exts = append(exts, extension.Strikethrough)
+		}
+		if opts.TaskLists {
+			exts = append(exts, extension.TaskList)
+		}
+		if opts.Autolinks {
+			exts = append(exts, extension.Linkify)
+		}
+	}
+
+	if opts.Footnotes {
+		exts = append(exts, extension.Footnote)
+	}
+	if opts.DefinitionLists {
+		exts = append(exts, extension.DefinitionList)
+	}
+
+	if opts.Highlight {
+		style := opts.HighlightStyle
+		if style == "" {
+			style = DefaultHighlightStyle
+		}
+
+		exts = append(exts, highlighting.NewHighlighting(
+			highlighting.WithStyle(style),
+			highlighting.WithFormatOptions(highlightFormatOptions(opts)...),
+		))
+	}
+
+	parserOpts := []parser.Option{}
+	if opts.HeadingIDs {
+		parserOpts = append(parserOpts, parser.WithAutoHeadingID())
+	}
+
+	rendererOpts := []goldmark.Option{}
+	if !opts.EscapeHTML {
+		rendererOpts = append(rendererOpts, goldmark.WithRendererOptions(gmhtml.WithUnsafe()))
+	}
+
+	return goldmark.New(append(rendererOpts,
+		goldmark.WithExtensions(exts...),
+		goldmark.WithParserOptions(parserOpts...),
+	)...)
+}
+
+// Markdown returns a function which reads the file at the provided path, relative to
+// the template's base directory, and converts its contents from Markdown to HTML using
+// the template's Markdown options. It also marks the file as updateable.
+func Markdown(m MarkdownRefer) func(string) (template.HTML, error) {
+	return func(path string) (template.HTML, error) {
+		path = FSPath(m.FS(), m.BaseDir(), path)
+
+		by, err := fs.ReadFile(m.FS(), path)
+		if err != nil {
+			return "", errors.Wrap(err, "fs: read file")
+		}
+
+		m.Ref(path)
+
+		return ConvertMarkdown(m.MarkdownOptions(), by)
+	}
+}
+
+// Markdownify returns a function which converts the provided Markdown to HTML using the
+// template's Markdown options.
+func Markdownify(m Markdowner) func(string) (template.HTML, error) {
+	return func(in string) (template.HTML, error) {
+		return ConvertMarkdown(m.MarkdownOptions(), []byte(in))
+	}
+}
+
+// ConvertMarkdown converts src from Markdown to HTML using the provided options.
+func ConvertMarkdown(opts MarkdownOptions, src []byte) (template.HTML, error) {
+	if opts.Highlight {
+		if _, err := highlightStyle(opts.HighlightStyle); err != nil {
+			return "", err
+		}
+	}
+
 	buf := bytes.Buffer{}
 
-	gm := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithUnsafe(),
-		),
-		goldmark.WithExtensions(
-			extension.NewTypographer(
-				extension.WithTypographicSubstitutions(extension.TypographicSubstitutions{
-					extension.EnDash: []byte("&mdash;"),
-				}),
-			),
-		),
-	)
-
-	if err := gm.Convert([]byte(in), &buf); err != nil {
+	if err := newMarkdown(opts).Convert(src, &buf); err != nil {
 		return "", errors.Wrap(err, "goldmark: convert")
 	}
 
-	return buf.String(), nil
+	return template.HTML(buf.String()), nil
+}
+
+// tocEntry is a heading in a table of contents, along with the headings nested under it.
+type tocEntry struct {
+	level    int
+	id       string
+	title    string
+	children []*tocEntry
+}
+
+// MarkdownTOC returns a function which returns a table of contents for the provided
+// Markdown as nested HTML lists, linking to each heading. Headings are given the same
+// ids as markdown gives them when HeadingIDs is set.
+func MarkdownTOC(m Markdowner) func(string) (template.HTML, error) {
+	return func(in string) (template.HTML, error) {
+		opts := m.MarkdownOptions()
+		opts.HeadingIDs = true
+
+		src := []byte(in)
+		doc := newMarkdown(opts).Parser().Parse(text.NewReader(src))
+
+		root := &tocEntry{}
+		stack := []*tocEntry{root}
+
+		err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			h, ok := n.(*ast.Heading)
+			if !ok || !entering {
+				return ast.WalkContinue, nil
+			}
+
+			entry := &tocEntry{level: h.Level, title: string(h.Text(src))}
+			if id, ok := h.AttributeString("id"); ok {
+				if b, ok := id.([]byte); ok {
+					entry.id = string(b)
+				}
+			}
+
+			for len(stack) > 1 && stack[len(stack)-1].level >= h.Level {
+				stack = stack[:len(stack)-1]
+			}
+
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, entry)
+			stack = append(stack, entry)
+
+			return ast.WalkSkipChildren, nil
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "goldmark: walk")
+		}
+
+		if len(root.children) == 0 {
+			return "", nil
+		}
+
+		b := strings.Builder{}
+		b.WriteString("<nav class=\"toc\">\n")
+		writeTOC(&b, root.children)
+		b.WriteString("</nav>\n")
+
+		return template.HTML(b.String()), nil
+	}
+}
+
+func writeTOC(b *strings.Builder, entries []*tocEntry) {
+	b.WriteString("<ul>\n")
+	for _, e := range entries {
+		b.WriteString("<li><a href=\"#")
+		b.WriteString(html.EscapeString(e.id))
+		b.WriteString("\">")
+		b.WriteString(html.EscapeString(e.title))
+		b.WriteString("</a>")
+
+		if len(e.children) > 0 {
+			b.WriteString("\n")
+			writeTOC(b, e.children)
+		}
+
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
 }
@@ -0,0 +1,301 @@
+package tmplfunc
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// number is a numeric template argument, which is either an integer or a float.
+type number struct {
+	i       int64
+	f       float64
+	isFloat bool
+}
+
+func (n number) float() float64 {
+	if n.isFloat {
+		return n.f
+	}
+
+	return float64(n.i)
+}
+
+// value returns n as an int if it's an integer and a float64 otherwise.
+func (n number) value() interface{} {
+	if n.isFloat {
+		return n.f
+	}
+
+	return int(n.i)
+}
+
+// toNumber converts ints, uints, floats, json.Numbers and numeric strings into a number.
+func toNumber(v interface{}) (number, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return parseNumber(string(v))
+	case string:
+		return parseNumber(v)
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{i: val.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return number{i: int64(val.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return number{f: val.Float(), isFloat: true}, nil
+	default:
+		return number{}, errors.Errorf("expected a number, got %T", v)
+	}
+}
+
+func parseNumber(s string) (number, error) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return number{i: i}, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return number{}, errors.Errorf("expected a number, got %q", s)
+	}
+
+	return number{f: f, isFloat: true}, nil
+}
+
+func toNumbers(v []interface{}) ([]number, error) {
+	tbr := make([]number, len(v))
+	for i, el := range v {
+		n, err := toNumber(el)
+		if err != nil {
+			return nil, err
+		}
+		tbr[i] = n
+	}
+
+	return tbr, nil
+}
+
+// toInt converts the provided number to an int, truncating any fractional part.
+func toInt(v interface{}) (int, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n.isFloat {
+		return int(n.f), nil
+	}
+
+	return int(n.i), nil
+}
+
+// toFloat converts the provided number to a float64.
+func toFloat(v interface{}) (float64, error) {
+	n, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return n.float(), nil
+}
+
+// reduce folds the provided numbers with intOp if they're all integers, and floatOp
+// otherwise.
+func reduce(args []interface{}, intOp func(a, b int64) (int64, error), floatOp func(a, b float64) (float64, error)) (interface{}, error) {
+	nums, err := toNumbers(args)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := nums[0]
+	for _, n := range nums[1:] {
+		if acc.isFloat || n.isFloat {
+			f, err := floatOp(acc.float(), n.float())
+			if err != nil {
+				return nil, err
+			}
+			acc = number{f: f, isFloat: true}
+			continue
+		}
+
+		i, err := intOp(acc.i, n.i)
+		if err != nil {
+			return nil, err
+		}
+		acc = number{i: i}
+	}
+
+	return acc.value(), nil
+}
+
+// errOverflow is returned when integer arithmetic overflows.
+var errOverflow = errors.New("integer overflow")
+
+// Add returns the sum of its arguments. The result is an int if every argument is an
+// integer, and a float64 otherwise. It returns an error if integers overflow.
+func Add(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) {
+			c := a + b
+			if (a^c)&(b^c) < 0 {
+				return 0, errOverflow
+			}
+			return c, nil
+		},
+		func(a, b float64) (float64, error) { return a + b, nil },
+	)
+}
+
+// Sub returns the first argument minus each of the rest.
+func Sub(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) {
+			c := a - b
+			if (a^b)&(a^c) < 0 {
+				return 0, errOverflow
+			}
+			return c, nil
+		},
+		func(a, b float64) (float64, error) { return a - b, nil },
+	)
+}
+
+// Mul returns the product of its arguments.
+func Mul(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) {
+			if a == 0 || b == 0 {
+				return 0, nil
+			}
+
+			c := a * b
+			if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+				return 0, errOverflow
+			}
+			return c, nil
+		},
+		func(a, b float64) (float64, error) { return a * b, nil },
+	)
+}
+
+// Div returns the first argument divided by each of the rest. Integers are divided
+// using integer division.
+func Div(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, errors.New("division by zero")
+			}
+			if a == math.MinInt64 && b == -1 {
+				return 0, errOverflow
+			}
+			return a / b, nil
+		},
+		func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return a / b, nil
+		},
+	)
+}
+
+// Mod returns the remainder of a divided by b.
+func Mod(a, b interface{}) (interface{}, error) {
+	return reduce([]interface{}{a, b},
+		func(a, b int64) (int64, error) {
+			if b == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return a % b, nil
+		},
+		func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return math.Mod(a, b), nil
+		},
+	)
+}
+
+// Min returns the smallest of its arguments.
+func Min(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) { return min(a, b), nil },
+		func(a, b float64) (float64, error) { return math.Min(a, b), nil },
+	)
+}
+
+// Max returns the largest of its arguments.
+func Max(a interface{}, b ...interface{}) (interface{}, error) {
+	return reduce(append([]interface{}{a}, b...),
+		func(a, b int64) (int64, error) { return max(a, b), nil },
+		func(a, b float64) (float64, error) { return math.Max(a, b), nil },
+	)
+}
+
+// Round rounds v to the nearest integer, or to the provided number of decimal places,
+// rounding half away from zero. Negative places round to the left of the decimal point,
+// so -2 rounds to the nearest hundred.
+func Round(v interface{}, places ...interface{}) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+
+	p := 0
+	if len(places) > 0 {
+		if p, err = toInt(places[0]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := checkPlaces(p); err != nil {
+		return 0, err
+	}
+
+	if p < -maxPlaces {
+		return 0, errors.Errorf("expected at least %d decimal places, got %d", -maxPlaces, p)
+	}
+
+	if p < 0 {
+		pow := math.Pow(10, float64(-p))
+		return math.Round(f/pow) * pow, nil
+	}
+
+	// A number too large to scale has no digits after the requested place.
+	pow := math.Pow(10, float64(p))
+	if math.IsInf(f*pow, 0) {
+		return f, nil
+	}
+
+	return math.Round(f*pow) / pow, nil
+}
+
+// Floor returns the greatest integer value less than or equal to v.
+func Floor(v interface{}) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Floor(f), nil
+}
+
+// Ceil returns the least integer value greater than or equal to v.
+func Ceil(v interface{}) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Ceil(f), nil
+}
@@ -0,0 +1,121 @@
+package tmplfunc
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(interface{}, ...interface{}) (interface{}, error)
+		args []interface{}
+		want interface{}
+	}{
+		{"add", Add, []interface{}{2, 3}, 5},
+		{"add mixed", Add, []interface{}{2, 2.5, "3"}, 7.5},
+		{"add json.Number", Add, []interface{}{json.Number("1"), uint8(2)}, 3},
+		{"sub", Sub, []interface{}{3, 2, 5}, -4},
+		{"mul", Mul, []interface{}{4, 1.5}, 6.0},
+		{"mul by zero", Mul, []interface{}{0, math.MinInt64}, 0},
+		{"div", Div, []interface{}{10, 4}, 2},
+		{"div float", Div, []interface{}{10, 4.0}, 2.5},
+		{"min", Min, []interface{}{3, 1, 2}, 1},
+		{"max", Max, []interface{}{3, 1.5, 2}, 3.0},
+		{"add at the limit", Add, []interface{}{math.MaxInt64 - 1, 1}, math.MaxInt64},
+		{"sub at the limit", Sub, []interface{}{math.MinInt64 + 1, 1}, math.MinInt64},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn(test.args[0], test.args[1:]...)
+		if err != nil {
+			t.Errorf("%s%v: %s", test.name, test.args, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s%v = %#v, want %#v", test.name, test.args, got, test.want)
+		}
+	}
+}
+
+func TestArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(interface{}, ...interface{}) (interface{}, error)
+		args []interface{}
+	}{
+		{"add overflow", Add, []interface{}{math.MaxInt64, 1}},
+		{"add underflow", Add, []interface{}{math.MinInt64, -1}},
+		{"sub overflow", Sub, []interface{}{math.MinInt64, 1}},
+		{"sub overflow", Sub, []interface{}{0, math.MinInt64}},
+		{"mul overflow", Mul, []interface{}{1 << 62, 2}},
+		{"mul overflow", Mul, []interface{}{math.MinInt64, -1}},
+		{"mul overflow", Mul, []interface{}{-1, math.MinInt64}},
+		{"div overflow", Div, []interface{}{math.MinInt64, -1}},
+		{"div by zero", Div, []interface{}{1, 0}},
+		{"div by zero float", Div, []interface{}{1.5, 0}},
+		{"not a number", Add, []interface{}{1, "one"}},
+	}
+
+	for _, test := range tests {
+		if got, err := test.fn(test.args[0], test.args[1:]...); err == nil {
+			t.Errorf("%s%v = %v, expected an error", test.name, test.args, got)
+		}
+	}
+}
+
+func TestMod(t *testing.T) {
+	if got, err := Mod(10, 3); err != nil || got != 1 {
+		t.Errorf("Mod(10, 3) = %v, %v, want 1", got, err)
+	}
+
+	if got, err := Mod(5.5, 2); err != nil || got != 1.5 {
+		t.Errorf("Mod(5.5, 2) = %v, %v, want 1.5", got, err)
+	}
+
+	if _, err := Mod(1, 0); err == nil {
+		t.Errorf("Mod(1, 0): expected an error")
+	}
+}
+
+func TestRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (float64, error)
+		want float64
+	}{
+		{"round", func() (float64, error) { return Round(2.5) }, 3},
+		{"round negative", func() (float64, error) { return Round(-2.5) }, -3},
+		{"round places", func() (float64, error) { return Round(3.14159, 2) }, 3.14},
+		{"round string", func() (float64, error) { return Round("1.25", "1") }, 1.3},
+		{"round to hundreds", func() (float64, error) { return Round(1250, -2) }, 1300},
+		{"round at the limit", func() (float64, error) { return Round(1.25, 20) }, 1.25},
+		{"round a huge number", func() (float64, error) { return Round(1e300, 20) }, 1e300},
+		{"round to the lowest place", func() (float64, error) { return Round(1e25, -20) }, 1e25},
+		{"floor", func() (float64, error) { return Floor(-1.5) }, -2},
+		{"ceil", func() (float64, error) { return Ceil(1.2) }, 2},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn()
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("%s = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRoundPlacesLimit(t *testing.T) {
+	for _, places := range []interface{}{21, 400, -21, "-400"} {
+		if got, err := Round(1.25, places); err == nil {
+			t.Errorf("Round(1.25, %v) = %v, expected an error", places, got)
+		}
+	}
+}
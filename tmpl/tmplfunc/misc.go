@@ -1,10 +1,15 @@
 package tmplfunc
 
 // Seq returns a list of integers between 0 and max. If max is 0,
-// nil is returned.
-func Seq(max int) []int {
+// nil is returned. max may be any number.
+func Seq(n interface{}) ([]int, error) {
+	max, err := toInt(n)
+	if err != nil {
+		return nil, err
+	}
+
 	if max < 0 {
-		return nil
+		return nil, nil
 	}
 
 	v := make([]int, 0, max)
@@ -12,15 +17,5 @@ func Seq(max int) []int {
 		v = append(v, i)
 	}
 
-	return v
-}
-
-// Add returns the sum of the two arguments.
-func Add(a, b int) int {
-	return a + b
-}
-
-// Sub returns the difference of the two arguments.
-func Sub(a, b int) int {
-	return a - b
+	return v, nil
 }
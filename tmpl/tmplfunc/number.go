@@ -0,0 +1,218 @@
+package tmplfunc
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// numberFormat describes how numbers are written.
+type numberFormat struct {
+	thousands string
+	decimal   string
+
+	// currencyAfter puts the currency symbol after the amount, separated by a space.
+	currencyAfter bool
+}
+
+var defaultNumberFormat = numberFormat{thousands: ",", decimal: "."}
+
+// numberFormats maps languages to the way they write numbers. Languages which aren't
+// listed use defaultNumberFormat.
+var numberFormats = map[string]numberFormat{
+	"de": {thousands: ".", decimal: ",", currencyAfter: true},
+	"es": {thousands: ".", decimal: ",", currencyAfter: true},
+	"fr": {thousands: "\u202f", decimal: ",", currencyAfter: true},
+	"it": {thousands: ".", decimal: ",", currencyAfter: true},
+	"nl": {thousands: ".", decimal: ","},
+	"pt": {thousands: ".", decimal: ",", currencyAfter: true},
+	"ru": {thousands: "\u00a0", decimal: ",", currencyAfter: true},
+	"pl": {thousands: "\u00a0", decimal: ",", currencyAfter: true},
+}
+
+// numberFormatFor returns the numberFormat for a locale like "de" or "pt-BR".
+func numberFormatFor(locale string) numberFormat {
+	lang, _, _ := strings.Cut(normalizeLocale(locale), "-")
+	if nf, ok := numberFormats[lang]; ok {
+		return nf
+	}
+
+	return defaultNumberFormat
+}
+
+// format writes f with the provided number of decimal places, grouping the integer
+// part in thousands.
+func (nf numberFormat) format(f float64, places int) string {
+	s := strconv.FormatFloat(math.Abs(f), 'f', max(places, 0), 64)
+
+	whole, frac, _ := strings.Cut(s, ".")
+
+	b := strings.Builder{}
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteString("-")
+	}
+
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(nf.thousands)
+		}
+		b.WriteRune(r)
+	}
+
+	if frac != "" {
+		b.WriteString(nf.decimal)
+		b.WriteString(frac)
+	}
+
+	return b.String()
+}
+
+// formatNumber writes n with as many decimal places as it needs.
+func (nf numberFormat) formatNumber(n number) string {
+	if !n.isFloat {
+		return nf.format(float64(n.i), 0)
+	}
+
+	s := strconv.FormatFloat(n.f, 'f', -1, 64)
+	_, frac, _ := strings.Cut(s, ".")
+	return nf.format(n.f, len(frac))
+}
+
+// currency writes f as an amount of the provided currency, which is either an ISO 4217
+// code like "USD" or a symbol to write with the amount.
+func (nf numberFormat) currency(currency string, f float64) string {
+	symbol, places := currency, 2
+	if c, ok := currencies[strings.ToUpper(currency)]; ok {
+		symbol, places = c.symbol, c.places
+	}
+
+	s := nf.format(f, places)
+	if nf.currencyAfter {
+		return s + "\u00a0" + strings.TrimSpace(symbol)
+	}
+
+	if strings.HasPrefix(s, "-") {
+		return "-" + symbol + s[1:]
+	}
+
+	return symbol + s
+}
+
+// currencies maps ISO 4217 codes to their symbols and the number of decimal places
+// they're usually written with.
+var currencies = map[string]struct {
+	symbol string
+	places int
+}{
+	"AUD": {"A$", 2},
+	"CAD": {"CA$", 2},
+	"CHF": {"CHF ", 2},
+	"CNY": {"CN¥", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"INR": {"₹", 2},
+	"JPY": {"¥", 0},
+	"KRW": {"₩", 0},
+	"USD": {"$", 2},
+}
+
+// maxPlaces is the most decimal places formatNumber and percent write.
+const maxPlaces = 20
+
+// checkPlaces returns an error if p is more decimal places than maxPlaces.
+func checkPlaces(p int) error {
+	if p > maxPlaces {
+		return errors.Errorf("expected at most %d decimal places, got %d", maxPlaces, p)
+	}
+
+	return nil
+}
+
+// FormatNumber returns a function which formats v with the provided number of decimal
+// places, grouping thousands the way the template's locale does.
+func FormatNumber(l Localizer) func(interface{}, interface{}) (string, error) {
+	return func(places interface{}, v interface{}) (string, error) {
+		p, err := toInt(places)
+		if err != nil {
+			return "", err
+		}
+
+		if err := checkPlaces(p); err != nil {
+			return "", err
+		}
+
+		f, err := toFloat(v)
+		if err != nil {
+			return "", err
+		}
+
+		return numberFormatFor(l.Locale()).format(f, p), nil
+	}
+}
+
+// FormatCurrency returns a function which formats v as an amount of the provided
+// currency, which is either an ISO 4217 code like "USD" or a symbol to write with the
+// amount. The amount is written the way the template's locale does.
+func FormatCurrency(l Localizer) func(string, interface{}) (string, error) {
+	return func(currency string, v interface{}) (string, error) {
+		f, err := toFloat(v)
+		if err != nil {
+			return "", err
+		}
+
+		return numberFormatFor(l.Locale()).currency(currency, f), nil
+	}
+}
+
+// Percent returns a function which formats v, a ratio, as a percentage with the provided
+// number of decimal places, written the way the template's locale does.
+func Percent(l Localizer) func(interface{}, interface{}) (string, error) {
+	return func(places interface{}, v interface{}) (string, error) {
+		p, err := toInt(places)
+		if err != nil {
+			return "", err
+		}
+
+		if err := checkPlaces(p); err != nil {
+			return "", err
+		}
+
+		f, err := toFloat(v)
+		if err != nil {
+			return "", err
+		}
+
+		nf := numberFormatFor(l.Locale())
+		if nf.currencyAfter {
+			return nf.format(f*100, p) + "\u00a0%", nil
+		}
+
+		return nf.format(f*100, p) + "%", nil
+	}
+}
+
+// HumanizeBytes formats a number of bytes using the largest binary unit (KB, MB, ...)
+// that keeps the value at or above 1.
+func HumanizeBytes(v interface{}) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+
+	units := []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+	i := 0
+	for math.Abs(f) >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+
+	if i == 0 {
+		return strconv.FormatFloat(f, 'f', -1, 64) + " " + units[i], nil
+	}
+
+	s := strconv.FormatFloat(f, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0") + " " + units[i], nil
+}
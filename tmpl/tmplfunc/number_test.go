@@ -0,0 +1,129 @@
+package tmplfunc
+
+import "testing"
+
+// testLocalizer is a Localizer for the locale it holds, without translations.
+type testLocalizer string
+
+func (l testLocalizer) Locale() string              { return string(l) }
+func (l testLocalizer) Translations() *Translations { return nil }
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		locale string
+		places interface{}
+		v      interface{}
+		want   string
+	}{
+		{"en", 2, 1234567.891, "1,234,567.89"},
+		{"en", 0, 999.5, "1,000"},
+		{"en", 1, -0.04, "0.0"},
+		{"en", 0, -1234, "-1,234"},
+		{"en", "3", "1.5", "1.500"},
+		{"de", 2, 1234567.891, "1.234.567,89"},
+		{"pt-BR", 1, 1234.5, "1.234,5"},
+		{"fr", 0, 12345, "12\u202f345"},
+		{"en", 20, 1, "1.00000000000000000000"},
+	}
+
+	for _, test := range tests {
+		got, err := FormatNumber(testLocalizer(test.locale))(test.places, test.v)
+		if err != nil {
+			t.Errorf("formatNumber %v %v (%s): %s", test.places, test.v, test.locale, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("formatNumber %v %v (%s) = %q, want %q", test.places, test.v, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		locale, currency string
+		v                interface{}
+		want             string
+	}{
+		{"en", "USD", 1234.5, "$1,234.50"},
+		{"en", "usd", -5, "-$5.00"},
+		{"en", "JPY", 1234.5, "¥1,234"},
+		{"de", "EUR", 1234.5, "1.234,50\u00a0€"},
+		{"en", "₿", 1, "₿1.00"},
+	}
+
+	for _, test := range tests {
+		got, err := FormatCurrency(testLocalizer(test.locale))(test.currency, test.v)
+		if err != nil {
+			t.Errorf("formatCurrency %s %v (%s): %s", test.currency, test.v, test.locale, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("formatCurrency %s %v (%s) = %q, want %q", test.currency, test.v, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		locale string
+		places interface{}
+		v      interface{}
+		want   string
+	}{
+		{"en", 1, 0.256, "25.6%"},
+		{"en", 0, 1.5, "150%"},
+		{"de", 1, 0.256, "25,6\u00a0%"},
+	}
+
+	for _, test := range tests {
+		got, err := Percent(testLocalizer(test.locale))(test.places, test.v)
+		if err != nil {
+			t.Errorf("percent %v %v (%s): %s", test.places, test.v, test.locale, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("percent %v %v (%s) = %q, want %q", test.places, test.v, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestPlacesLimit(t *testing.T) {
+	l := testLocalizer("en")
+
+	if _, err := FormatNumber(l)(21, 1); err == nil {
+		t.Errorf("formatNumber 21 1: expected an error")
+	}
+
+	if _, err := Percent(l)(1000, 0.5); err == nil {
+		t.Errorf("percent 1000 0.5: expected an error")
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1 KB"},
+		{1536, "1.5 KB"},
+		{"1048576", "1 MB"},
+		{-2048, "-2 KB"},
+	}
+
+	for _, test := range tests {
+		got, err := HumanizeBytes(test.v)
+		if err != nil {
+			t.Errorf("HumanizeBytes(%v): %s", test.v, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("HumanizeBytes(%v) = %q, want %q", test.v, got, test.want)
+		}
+	}
+}
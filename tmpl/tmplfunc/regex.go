@@ -0,0 +1,98 @@
+package tmplfunc
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// maxCachedRegexes is the most patterns regexCache holds. Templates use a handful of
+// patterns, but ones built from data, like a search term, could otherwise grow it
+// without limit over a long watch session.
+const maxCachedRegexes = 256
+
+// regexCache holds the patterns compiled by the regex functions, so each distinct
+// pattern is usually only compiled once per process. It's emptied when it's full.
+var regexCache = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{
+	m: map[string]*regexp.Regexp{},
+}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, ok := regexCache.m[pattern]
+	regexCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "regexp: compile")
+	}
+
+	regexCache.Lock()
+	if len(regexCache.m) >= maxCachedRegexes {
+		regexCache.m = map[string]*regexp.Regexp{}
+	}
+	regexCache.m[pattern] = re
+	regexCache.Unlock()
+
+	return re, nil
+}
+
+// RegexMatch reports whether s contains a match of the provided pattern.
+func RegexMatch(pattern, s string) (bool, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(s), nil
+}
+
+// RegexFind returns the first match of the provided pattern in s, or an empty string.
+func RegexFind(pattern, s string) (string, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return re.FindString(s), nil
+}
+
+// RegexFindAll returns up to n matches of the provided pattern in s. If n is negative,
+// every match is returned.
+func RegexFindAll(pattern, s string, n int) ([]string, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return re.FindAllString(s, n), nil
+}
+
+// RegexReplace replaces every match of the provided pattern in s with repl, expanding
+// $1-style references to submatches.
+func RegexReplace(pattern, s, repl string) (string, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return re.ReplaceAllString(s, repl), nil
+}
+
+// RegexSplit splits s around matches of the provided pattern, returning at most n
+// substrings. If n is negative, every substring is returned.
+func RegexSplit(pattern, s string, n int) ([]string, error) {
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return re.Split(s, n), nil
+}
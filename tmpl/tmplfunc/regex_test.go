@@ -0,0 +1,64 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (interface{}, error)
+		want interface{}
+	}{
+		{"match", func() (interface{}, error) { return RegexMatch(`^\d+$`, "123") }, true},
+		{"no match", func() (interface{}, error) { return RegexMatch(`^\d+$`, "12a") }, false},
+		{"find", func() (interface{}, error) { return RegexFind(`[a-z]+`, "12ab34cd") }, "ab"},
+		{"find nothing", func() (interface{}, error) { return RegexFind(`x`, "abc") }, ""},
+		{"find all", func() (interface{}, error) { return RegexFindAll(`\d`, "a1b2c3", -1) }, []string{"1", "2", "3"}},
+		{"find all limited", func() (interface{}, error) { return RegexFindAll(`\d`, "a1b2c3", 2) }, []string{"1", "2"}},
+		{"replace", func() (interface{}, error) { return RegexReplace(`(\w+)@(\w+)`, "me@host", "$2 at ${1}") }, "host at me"},
+		{"split", func() (interface{}, error) { return RegexSplit(`\s*,\s*`, "a , b,c", -1) }, []string{"a", "b", "c"}},
+		{"split limited", func() (interface{}, error) { return RegexSplit(`,`, "a,b,c", 2) }, []string{"a", "b,c"}},
+	}
+
+	for _, test := range tests {
+		got, err := test.fn()
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRegexInvalidPattern(t *testing.T) {
+	if _, err := RegexMatch(`(`, "abc"); err == nil {
+		t.Errorf("RegexMatch with an invalid pattern: expected an error")
+	}
+
+	if _, err := RegexReplace(`[`, "abc", ""); err == nil {
+		t.Errorf("RegexReplace with an invalid pattern: expected an error")
+	}
+}
+
+func TestRegexCacheBounded(t *testing.T) {
+	for i := 0; i < maxCachedRegexes*3; i++ {
+		pattern := "^" + strconv.Itoa(i) + "$"
+		if ok, err := RegexMatch(pattern, strconv.Itoa(i)); err != nil || !ok {
+			t.Fatalf("regexMatch(%q) = %v, %v, want true", pattern, ok, err)
+		}
+
+		regexCache.RLock()
+		n := len(regexCache.m)
+		regexCache.RUnlock()
+
+		if n > maxCachedRegexes {
+			t.Fatalf("after %d patterns, the cache holds %d, want at most %d", i+1, n, maxCachedRegexes)
+		}
+	}
+}
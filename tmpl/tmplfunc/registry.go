@@ -0,0 +1,104 @@
+package tmplfunc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Meta documents a registered function.
+type Meta struct {
+	// Doc describes what the function does.
+	Doc string
+
+	// Example is a snippet of template code which uses the function.
+	Example string
+}
+
+// Context is the template a Factory builds a function for.
+type Context interface {
+	Refer
+	Filesystem
+	Moder
+	Localizer
+	Markdowner
+	Asseter
+	CSPHasher
+	Minifier
+
+	Now() time.Time
+	EnvVars() map[string]string
+}
+
+// Factory builds a template function for a particular template. Register a Factory
+// for functions which depend on the template, like inline or env.
+type Factory func(Context) interface{}
+
+// Func is a registered template function.
+type Func struct {
+	Name string
+	Fn   interface{}
+	Meta Meta
+}
+
+// Resolve returns the function to add to the template's function map. If the function
+// was registered as a Factory, it's built for the provided Context.
+func (f Func) Resolve(c Context) interface{} {
+	if factory, ok := f.Fn.(Factory); ok {
+		return factory(c)
+	}
+
+	return f.Fn
+}
+
+var registry = struct {
+	sync.RWMutex
+	funcs map[string]Func
+}{
+	funcs: map[string]Func{},
+}
+
+// Register makes fn available in every template under the provided name, replacing
+// any function previously registered with that name. fn may be a Factory.
+func Register(name string, fn interface{}, meta Meta) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.funcs[name] = Func{
+		Name: name,
+		Fn:   fn,
+		Meta: meta,
+	}
+}
+
+// Unregister removes the function registered under the provided name.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.funcs, name)
+}
+
+// Lookup returns the function registered under the provided name.
+func Lookup(name string) (Func, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	f, ok := registry.funcs[name]
+	return f, ok
+}
+
+// Registered returns every registered function, sorted by name.
+func Registered() []Func {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	tbr := make([]Func, 0, len(registry.funcs))
+	for _, f := range registry.funcs {
+		tbr = append(tbr, f)
+	}
+
+	sort.Slice(tbr, func(i, j int) bool { return tbr[i].Name < tbr[j].Name })
+
+	return tbr
+}
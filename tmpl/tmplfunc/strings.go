@@ -0,0 +1,158 @@
+package tmplfunc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Upper returns s in upper case.
+func Upper(s string) string { return strings.ToUpper(s) }
+
+// Lower returns s in lower case.
+func Lower(s string) string { return strings.ToLower(s) }
+
+// Title returns s with the first letter of each word in upper case.
+func Title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		start := unicode.IsSpace(prev) || unicode.IsPunct(prev) && prev != '\''
+		prev = r
+
+		if start {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// Trim returns s without leading and trailing whitespace.
+func Trim(s string) string { return strings.TrimSpace(s) }
+
+// TrimPrefix returns s without the provided prefix.
+func TrimPrefix(prefix, s string) string { return strings.TrimPrefix(s, prefix) }
+
+// TrimSuffix returns s without the provided suffix.
+func TrimSuffix(suffix, s string) string { return strings.TrimSuffix(s, suffix) }
+
+// Replace returns src with every instance of old replaced with new.
+func Replace(old, new, src string) string { return strings.ReplaceAll(src, old, new) }
+
+// Repeat returns s repeated count times.
+func Repeat(count int, s string) string {
+	if count < 0 {
+		return ""
+	}
+
+	return strings.Repeat(s, count)
+}
+
+// Split splits s around each instance of sep and returns a map with keys _0, _1 and so
+// on, like Sprig's split.
+func Split(sep, s string) map[string]string {
+	parts := strings.Split(s, sep)
+
+	tbr := make(map[string]string, len(parts))
+	for i, p := range parts {
+		tbr[fmt.Sprintf("_%d", i)] = p
+	}
+
+	return tbr
+}
+
+// SplitList splits s around each instance of sep.
+func SplitList(sep, s string) []string { return strings.Split(s, sep) }
+
+// Join joins the elements of the provided list with sep, converting each element to a
+// string.
+func Join(sep string, v interface{}) (string, error) {
+	list, err := toSlice(v)
+	if err != nil {
+		return "", err
+	}
+
+	strs := make([]string, 0, len(list))
+	for _, el := range list {
+		if el != nil {
+			strs = append(strs, toString(el))
+		}
+	}
+
+	return strings.Join(strs, sep), nil
+}
+
+// Contains reports whether substr is within s.
+func Contains(substr, s string) bool { return strings.Contains(s, substr) }
+
+// HasPrefix reports whether s begins with prefix.
+func HasPrefix(prefix, s string) bool { return strings.HasPrefix(s, prefix) }
+
+// HasSuffix reports whether s ends with suffix.
+func HasSuffix(suffix, s string) bool { return strings.HasSuffix(s, suffix) }
+
+// Truncate returns the first n characters of s. If n is negative, it returns the last
+// -n characters instead.
+func Truncate(n int, s string) string {
+	count := utf8.RuneCountInString(s)
+	switch {
+	case n >= 0 && count > n:
+		return string([]rune(s)[:n])
+	case n < 0 && count > -n:
+		return string([]rune(s)[count+n:])
+	}
+
+	return s
+}
+
+// Indent indents every line of s with n spaces.
+func Indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// NIndent is like Indent, but adds a newline before s.
+func NIndent(n int, s string) string {
+	return "\n" + Indent(n, s)
+}
+
+// Quote wraps each argument in double quotes, escaping as needed, and joins them with
+// spaces. Nil arguments are skipped.
+func Quote(v ...interface{}) string {
+	strs := make([]string, 0, len(v))
+	for _, el := range v {
+		if el != nil {
+			strs = append(strs, fmt.Sprintf("%q", toString(el)))
+		}
+	}
+
+	return strings.Join(strs, " ")
+}
+
+// SQuote wraps each argument in single quotes and joins them with spaces. Nil arguments
+// are skipped.
+func SQuote(v ...interface{}) string {
+	strs := make([]string, 0, len(v))
+	for _, el := range v {
+		if el != nil {
+			strs = append(strs, "'"+toString(el)+"'")
+		}
+	}
+
+	return strings.Join(strs, " ")
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,140 @@
+package tmplfunc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"hello world", "Hello World"},
+		{"it's a dog-eat-dog world", "It's A Dog-Eat-Dog World"},
+		{"(quoted) text", "(Quoted) Text"},
+		{"élan vital", "Élan Vital"},
+	}
+
+	for _, test := range tests {
+		if got := Title(test.in); got != test.want {
+			t.Errorf("Title(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		n        int
+		in, want string
+	}{
+		{3, "hello", "hel"},
+		{10, "hello", "hello"},
+		{0, "hello", ""},
+		{-2, "hello", "lo"},
+		{-10, "hello", "hello"},
+		{2, "héllo", "hé"},
+	}
+
+	for _, test := range tests {
+		if got := Truncate(test.n, test.in); got != test.want {
+			t.Errorf("Truncate(%d, %q) = %q, want %q", test.n, test.in, got, test.want)
+		}
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	tests := []struct {
+		count    int
+		in, want string
+	}{
+		{3, "ab", "ababab"},
+		{0, "ab", ""},
+		{-1, "ab", ""},
+	}
+
+	for _, test := range tests {
+		if got := Repeat(test.count, test.in); got != test.want {
+			t.Errorf("Repeat(%d, %q) = %q, want %q", test.count, test.in, got, test.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := Split(",", "a,b,,c")
+	want := map[string]string{"_0": "a", "_1": "b", "_2": "", "_3": "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Split = %v, want %v", got, want)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		sep  string
+		in   interface{}
+		want string
+	}{
+		{", ", []string{"a", "b"}, "a, b"},
+		{"-", []interface{}{1, nil, 2.5, true}, "1-2.5-true"},
+		{",", nil, ""},
+	}
+
+	for _, test := range tests {
+		got, err := Join(test.sep, test.in)
+		if err != nil {
+			t.Errorf("Join(%q, %v): %s", test.sep, test.in, err)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("Join(%q, %v) = %q, want %q", test.sep, test.in, got, test.want)
+		}
+	}
+
+	if _, err := Join(",", 5); err == nil {
+		t.Errorf("Join(\",\", 5): expected an error")
+	}
+}
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		n        int
+		in, want string
+	}{
+		{2, "a\nb", "  a\n  b"},
+		{0, "a\nb", "a\nb"},
+		{4, "", "    "},
+	}
+
+	for _, test := range tests {
+		if got := Indent(test.n, test.in); got != test.want {
+			t.Errorf("Indent(%d, %q) = %q, want %q", test.n, test.in, got, test.want)
+		}
+	}
+
+	if got, want := NIndent(2, "a"), "\n  a"; got != want {
+		t.Errorf("NIndent(2, \"a\") = %q, want %q", got, want)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in       []interface{}
+		want, sq string
+	}{
+		{[]interface{}{"a", "b"}, `"a" "b"`, `'a' 'b'`},
+		{[]interface{}{`say "hi"`}, `"say \"hi\""`, `'say "hi"'`},
+		{[]interface{}{1, nil, true}, `"1" "true"`, `'1' 'true'`},
+		{nil, ``, ``},
+	}
+
+	for _, test := range tests {
+		if got := Quote(test.in...); got != test.want {
+			t.Errorf("Quote(%v) = %s, want %s", test.in, got, test.want)
+		}
+
+		if got := SQuote(test.in...); got != test.sq {
+			t.Errorf("SQuote(%v) = %s, want %s", test.in, got, test.sq)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package tmplfunc
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations maps letters which don't decompose into ASCII to their closest
+// ASCII equivalents.
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th",
+	'ı': "i", 'ħ': "h", 'ŧ': "t", 'ŋ': "ng",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// Slugify converts s into a lowercase, URL-friendly slug. Accented Latin, Greek and
+// Cyrillic letters are reduced to their base letters, Greek and Cyrillic letters are
+// transliterated, and any run of characters other than letters and digits becomes a
+// single hyphen. Letters from other scripts are kept as-is.
+func Slugify(s string) string {
+	b := strings.Builder{}
+	hyphen := false
+
+	write := func(s string) {
+		if hyphen && s != "" {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(s)
+	}
+
+	// Composing first keeps letters like й and ё, which are transliterated as a whole,
+	// together even if s spells them with combining marks.
+	marks := false
+	for _, r := range norm.NFC.String(strings.ToLower(s)) {
+		if t, ok := transliterations[r]; ok {
+			write(t)
+			marks = true
+			continue
+		}
+
+		// Marks left over after composing, like a stress accent on a Cyrillic vowel,
+		// belong to the letter before them.
+		if marks && unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			hyphen = b.Len() > 0
+			marks = false
+			continue
+		}
+
+		marks = unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+		if !marks {
+			write(string(r))
+			continue
+		}
+
+		for _, d := range norm.NFD.String(string(r)) {
+			if unicode.Is(unicode.Mn, d) {
+				continue
+			}
+
+			if t, ok := transliterations[d]; ok {
+				write(t)
+			} else {
+				write(string(d))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// WordCount returns the number of words in s, ignoring any HTML tags.
+func WordCount(s interface{}) int {
+	return len(strings.Fields(StripHTML(s)))
+}
+
+// ReadingTime returns the number of minutes it takes to read s, rounded up, at 200 words
+// per minute or the provided rate.
+func ReadingTime(s interface{}, wpm ...int) int {
+	rate := 200
+	if len(wpm) > 0 && wpm[0] > 0 {
+		rate = wpm[0]
+	}
+
+	return int(math.Ceil(float64(WordCount(s)) / float64(rate)))
+}
+
+// Wrap wraps s so that no line is longer than width characters, breaking lines between
+// words. Words longer than width are left intact.
+func Wrap(width int, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		b := strings.Builder{}
+		n := 0
+		for _, word := range strings.Fields(line) {
+			l := len([]rune(word))
+			switch {
+			case n == 0:
+			case n+1+l > width:
+				b.WriteByte('\n')
+				n = 0
+			default:
+				b.WriteByte(' ')
+				n++
+			}
+
+			b.WriteString(word)
+			n += l
+		}
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
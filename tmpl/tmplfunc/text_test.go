@@ -0,0 +1,73 @@
+package tmplfunc
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  --Already-slugged--  ", "already-slugged"},
+		{"Crème Brûlée", "creme-brulee"},
+		{"Straße & Smørrebrød", "strasse-smorrebrod"},
+		{"Ελλάδα", "ellada"},
+		{"Ἀθῆναι", "athinai"},
+		{"Ёлка и йод", "yolka-i-yod"},
+		{"Ёлка", "yolka"},
+		{"ѐж", "ezh"},
+		{"ударе́ние", "udarenie"},
+		{"Київ", "kiyiv"},
+		{"日本語 テキスト", "日本語-テキスト"},
+		{"が", "が"},
+		{"2024 Recap", "2024-recap"},
+	}
+
+	for _, test := range tests {
+		if got := Slugify(test.in); got != test.want {
+			t.Errorf("Slugify(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	tests := []struct {
+		in      string
+		words   int
+		minutes int
+	}{
+		{"", 0, 0},
+		{"<p>One <b>two</b> three</p>", 3, 1},
+		{"word ", 1, 1},
+	}
+
+	for _, test := range tests {
+		if got := WordCount(test.in); got != test.words {
+			t.Errorf("WordCount(%q) = %d, want %d", test.in, got, test.words)
+		}
+
+		if got := ReadingTime(test.in); got != test.minutes {
+			t.Errorf("ReadingTime(%q) = %d, want %d", test.in, got, test.minutes)
+		}
+	}
+
+	if got := ReadingTime("a b c d e", 2); got != 3 {
+		t.Errorf("ReadingTime at 2 wpm = %d, want 3", got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		width    int
+		in, want string
+	}{
+		{10, "the quick brown fox", "the quick\nbrown fox"},
+		{3, "a verylongword b", "a\nverylongword\nb"},
+		{20, "one\ntwo  three", "one\ntwo three"},
+	}
+
+	for _, test := range tests {
+		if got := Wrap(test.width, test.in); got != test.want {
+			t.Errorf("Wrap(%d, %q) = %q, want %q", test.width, test.in, got, test.want)
+		}
+	}
+}
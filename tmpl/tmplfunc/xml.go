@@ -0,0 +1,21 @@
+package tmplfunc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// XMLEscape returns s with the characters which are special in XML text and attribute
+// values escaped.
+func XMLEscape(s interface{}) string {
+	buf := bytes.Buffer{}
+	xml.EscapeText(&buf, []byte(toString(s)))
+	return buf.String()
+}
+
+// CDATA wraps s in a CDATA section, so it can contain markup without escaping. Any "]]>"
+// in s is split across two sections.
+func CDATA(s interface{}) string {
+	return "<![CDATA[" + strings.ReplaceAll(toString(s), "]]>", "]]]]><![CDATA[>") + "]]>"
+}
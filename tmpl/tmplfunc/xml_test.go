@@ -0,0 +1,37 @@
+package tmplfunc
+
+import "testing"
+
+func TestXMLEscape(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{`Tom & "Jerry" <3`, "Tom &amp; &#34;Jerry&#34; &lt;3"},
+		{"it's", "it&#39;s"},
+		{42, "42"},
+	}
+
+	for _, test := range tests {
+		if got := XMLEscape(test.in); got != test.want {
+			t.Errorf("xmlEscape(%v) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestCDATA(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{"<p>hi</p>", "<![CDATA[<p>hi</p>]]>"},
+		{"a]]>b", "<![CDATA[a]]]]><![CDATA[>b]]>"},
+		{"", "<![CDATA[]]>"},
+	}
+
+	for _, test := range tests {
+		if got := CDATA(test.in); got != test.want {
+			t.Errorf("cdata(%v) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
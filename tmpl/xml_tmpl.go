@@ -0,0 +1,205 @@
+package tmpl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	text "text/template"
+
+	"github.com/pkg/errors"
+)
+
+type XMLTmpl struct {
+	*Tmpl
+
+	Minify bool
+
+	// Indent, if set, reformats the output with each element on its own line, indented
+	// by Indent per level. It has no effect if Minify is set.
+	Indent string
+}
+
+func (t *XMLTmpl) WithMinify(m bool) *XMLTmpl {
+	t.Minify = m
+	return t
+}
+
+func (t *XMLTmpl) WithIndent(indent string) *XMLTmpl {
+	t.Indent = indent
+	return t
+}
+
+func (t *XMLTmpl) Execute(out io.Writer, in io.Reader) error {
+	buf := bytes.Buffer{}
+	if _, err := io.Copy(&buf, in); err != nil {
+		return errors.Wrap(err, "io: copy (input)")
+	}
+
+	tmpl, err := text.New("output").Funcs(t.funcs()).Delims(t.leftDelim, t.rightDelim).Parse(buf.String())
+	if err != nil {
+		return errors.Wrap(err, "compile template")
+	}
+
+	buf.Reset()
+
+	if err := tmpl.Execute(&buf, t); err != nil {
+		return errors.Wrap(err, "execute template")
+	}
+
+	if err := validateXML(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "xml: validate")
+	}
+
+	dst := &buf
+	if t.Minify {
+		by, err := t.Tmpl.Minify("application/xml", buf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		dst = bytes.NewBuffer(by)
+	} else if t.Indent != "" {
+		dst = &bytes.Buffer{}
+		if err := indentXML(dst, buf.Bytes(), t.Indent); err != nil {
+			return errors.Wrap(err, "xml: indent")
+		}
+	}
+
+	if _, err := io.Copy(out, dst); err != nil {
+		return errors.Wrap(err, "io: copy (output)")
+	}
+
+	return nil
+}
+
+// validateXML returns an error describing the first problem in doc if it isn't a
+// well-formed XML document.
+func validateXML(doc []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+
+	roots := 0
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(tok.(xml.CharData))) > 0 {
+				line, _ := d.InputPos()
+				return errors.Errorf("line %d: text outside the root element", line)
+			}
+		}
+	}
+
+	if roots != 1 {
+		return errors.Errorf("expected one root element, found %d", roots)
+	}
+
+	return nil
+}
+
+// indentXML writes doc to w with each element on its own line. Elements which contain
+// only text are kept on one line, and whitespace between elements is dropped.
+func indentXML(w *bytes.Buffer, doc []byte, indent string) error {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+
+	depth := 0
+	open := false    // the last start tag hasn't been closed with ">" yet
+	inline := false  // the current element has text, so its end tag stays on the line
+	started := false // something has been written
+
+	newline := func(depth int) {
+		if started {
+			w.WriteString("\n")
+		}
+		w.WriteString(strings.Repeat(indent, depth))
+		started = true
+	}
+
+	closeOpen := func() {
+		if open {
+			w.WriteString(">")
+			open = false
+		}
+	}
+
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			closeOpen()
+			newline(depth)
+			w.WriteString("<" + xmlName(tok.Name))
+			for _, attr := range tok.Attr {
+				w.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(w, []byte(attr.Value))
+				w.WriteString(`"`)
+			}
+			open, inline = true, false
+			depth++
+		case xml.EndElement:
+			depth--
+			if open {
+				w.WriteString("/>")
+				open = false
+			} else {
+				if !inline {
+					newline(depth)
+				}
+				w.WriteString("</" + xmlName(tok.Name) + ">")
+			}
+			inline = false
+		case xml.CharData:
+			if len(bytes.TrimSpace(tok)) == 0 {
+				continue
+			}
+			closeOpen()
+			xml.EscapeText(w, tok)
+			inline = true
+		case xml.Comment:
+			closeOpen()
+			newline(depth)
+			w.WriteString("<!--" + string(tok) + "-->")
+		case xml.ProcInst:
+			closeOpen()
+			newline(depth)
+			w.WriteString("<?" + tok.Target + " " + string(tok.Inst) + "?>")
+		case xml.Directive:
+			closeOpen()
+			newline(depth)
+			w.WriteString("<!" + string(tok) + ">")
+		}
+	}
+
+	w.WriteString("\n")
+	return nil
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+
+	return n.Space + ":" + n.Local
+}
@@ -0,0 +1,73 @@
+package tmpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestXMLTmpl(t *testing.T) {
+	const feed = `<?xml version="1.0"?>
+<!-- feed -->
+<rss version="2.0">   <channel>
+<title>{{ "Tom & Jerry" | xmlEscape }}</title><item a="1"><description>{{ cdata "<p>hi</p>" }}</description></item>
+<empty></empty>
+</channel></rss>`
+
+	tests := []struct {
+		name   string
+		minify bool
+		indent string
+		want   string
+	}{
+		{"unchanged", false, "", `<?xml version="1.0"?>
+<!-- feed -->
+<rss version="2.0">   <channel>
+<title>Tom &amp; Jerry</title><item a="1"><description><![CDATA[<p>hi</p>]]></description></item>
+<empty></empty>
+</channel></rss>`},
+		{"indent", false, "  ", `<?xml version="1.0"?>
+<!-- feed -->
+<rss version="2.0">
+  <channel>
+    <title>Tom &amp; Jerry</title>
+    <item a="1">
+      <description>&lt;p&gt;hi&lt;/p&gt;</description>
+    </item>
+    <empty/>
+  </channel>
+</rss>
+`},
+		{"minify", true, "  ", `<?xml version="1.0"?><rss version="2.0"><channel><title>Tom &amp; Jerry</title><item a="1"><description>&lt;p>hi&lt;/p></description></item><empty/></channel></rss>`},
+	}
+
+	for _, test := range tests {
+		buf := bytes.Buffer{}
+		err := New().XML().WithMinify(test.minify).WithIndent(test.indent).Execute(&buf, strings.NewReader(feed))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got := buf.String(); got != test.want {
+			t.Errorf("%s = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestXMLTmplInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unclosed":        `<a><b></a>`,
+		"two roots":       `<a/><b/>`,
+		"no root":         `<?xml version="1.0"?>`,
+		"text after root": `<a/>oops`,
+		"unescaped":       `<a>{{ "Tom & Jerry" }}</a>`,
+		"template":        `<a>{{ index (list) 1 }}</a>`,
+	}
+
+	for name, in := range tests {
+		if err := New().XML().Execute(&bytes.Buffer{}, strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}